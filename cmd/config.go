@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/edgestats/edgestats-client/data"
+	"github.com/edgestats/edgestats-client/handlers"
+)
+
+func configCmd(args []string) int {
+	fs := newFlagSet("config")
+	if code, stop := parseFlags(fs, args, 1); stop {
+		return code
+	}
+
+	switch fs.Arg(0) {
+	case "show":
+		showConfig()
+		return exitOK
+	case "check":
+		if _, err := setup(); err != nil {
+			fmt.Println("Config error:", err)
+			return exitError
+		}
+		if _, err := handlers.WatchdogFromEnv(); err != nil {
+			fmt.Println("Config error:", err)
+			return exitError
+		}
+		if _, err := data.HeartbeatIntervalFromEnv(); err != nil {
+			fmt.Println("Config error:", err)
+			return exitError
+		}
+		if _, err := data.IncidentWindowFromEnv(); err != nil {
+			fmt.Println("Config error:", err)
+			return exitError
+		}
+		fmt.Println("Config ok")
+		return exitOK
+	default:
+		fmt.Fprintf(fs.Output(), "Unknown config action: %s\n\n", fs.Arg(0))
+		fs.Usage()
+		return exitUsage
+	}
+}
+
+func showConfig() {
+	fmt.Printf("%-26s %s\n", "server", data.ServerAddr())
+	if fp, err := handlers.GetFilePath(runtime.GOOS); err == nil {
+		fmt.Printf("%-26s %s\n", "log file", fp)
+	}
+	fmt.Printf("%-26s %s\n", "status api", handlers.GetStatusAddr())
+	fmt.Println()
+
+	for _, k := range data.EnvVars() {
+		v, ok := os.LookupEnv(k)
+		switch true {
+		case !ok:
+			v = "(default)"
+		case data.IsSecretEnv(k) && v != "":
+			v = "(set)"
+		}
+		fmt.Printf("%-26s %s\n", k, v)
+	}
+}
+
+func setupLog() error {
+	// apply env config for parsing log lines
+	if err := data.SetLogLocation(os.Getenv("LOG_TIMEZONE")); err != nil {
+		return err
+	}
+
+	if err := data.SetTimeSource(os.Getenv("LOG_TIME_SOURCE")); err != nil {
+		return err
+	}
+
+	ph, err := data.PeerHistoryFromEnv()
+	if err != nil {
+		return err
+	}
+	data.SetPeerHistory(ph)
+
+	ct, err := data.ConsensusTrackerFromEnv()
+	if err != nil {
+		return err
+	}
+	data.SetConsensusTracker(ct)
+
+	re, err := data.RewardEstimatorFromEnv()
+	if err != nil {
+		return err
+	}
+	data.SetRewardEstimator(re)
+
+	return data.SetNodeAddress(os.Getenv("NODE_ADDRESS"))
+}
+
+func setup() (*data.Scheduler, error) {
+	// apply env config shared by run and replay
+	if err := setupLog(); err != nil {
+		return nil, err
+	}
+
+	if err := data.SetDedupFile(os.Getenv("DEDUP_FILEPATH")); err != nil {
+		fmt.Println("Warning: sent records not persisted:", err)
+	}
+
+	if err := data.SetUnparsedFile(os.Getenv("UNPARSED_FILEPATH")); err != nil {
+		fmt.Println("Warning: unparsed lines not quarantined:", err)
+	}
+
+	if err := data.SetValidation(os.Getenv("VALIDATION"), os.Getenv("VALIDATION_QUARANTINE_FILE")); err != nil {
+		return nil, err
+	}
+	skew, err := data.ValidationSkewFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	data.SetValidationSkew(skew)
+
+	tc, err := data.NewTLSConfig(data.TLSOptionsFromEnv())
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := data.TransportOptionsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	hc, err := data.NewHTTPClient(tc, to)
+	if err != nil {
+		return nil, err
+	}
+	data.SetHTTPClient(hc)
+
+	creds, err := data.CredentialsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	data.SetCredentialProvider(creds)
+	data.SetRequestSigning(os.Getenv("API_SIGNING") == "hmac")
+
+	if err := data.CompressionFromEnv(); err != nil {
+		fmt.Println("Warning: request compression disabled:", err)
+	}
+
+	sched, err := data.SchedulerFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	data.SetScheduler(sched)
+
+	return sched, nil
+}
+
+func bootstrap(fp string) {
+	// seed node address and peers, records before then are held
+	ok, err := handlers.Bootstrap(fp)
+	switch true {
+	case err != nil:
+		fmt.Println("Warning: node not bootstrapped:", err)
+	case !ok:
+		fmt.Println("Warning: node not bootstrapped from log history, records held until address and peers are seen")
+	default:
+		addr, np, sp := data.NodeInfo()
+		fmt.Printf("Node address: %s, peers: %d/%d\n", addr, np, sp)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/edgestats/edgestats-client/data"
+	"github.com/edgestats/edgestats-client/handlers"
+)
+
+func doctorCmd(args []string) int {
+	fs := newFlagSet("doctor")
+	wait := fs.Duration("wait", 10*time.Second, "how long to wait for the log to be written")
+	if code, stop := parseFlags(fs, args, 0); stop {
+		return code
+	}
+
+	var checks []handlers.Check
+
+	// config first, server check needs transport and key
+	_, err := setup()
+	if err != nil {
+		checks = append(checks, handlers.Check{Name: "config", Status: handlers.CheckFail, Detail: err.Error(), Hint: "run 'edgestats-client config show' and fix the variable named above"})
+	} else {
+		checks = append(checks, handlers.Check{Name: "config", Status: handlers.CheckPass, Detail: "environment ok"})
+	}
+
+	fp, err := handlers.GetFilePath(runtime.GOOS)
+	if err != nil {
+		checks = append(checks, handlers.Check{Name: "log file", Status: handlers.CheckFail, Detail: err.Error(), Hint: "set LOG_FILEPATH to the edge node log.log"})
+	} else {
+		fmt.Printf("Checking %s, waiting %v for writes...\n", fp, *wait)
+		checks = append(checks, handlers.Diagnose(fp, *wait)...)
+	}
+
+	if checks[0].Status == handlers.CheckPass {
+		checks = append(checks, checkServer())
+	}
+
+	// report, fail exits 1
+	code := exitOK
+	fmt.Println()
+	for _, c := range checks {
+		fmt.Printf("%s  %-14s %s\n", c.Status, c.Name, c.Detail)
+		if c.Hint != "" && c.Status != handlers.CheckPass {
+			fmt.Printf("      %-14s -> %s\n", "", c.Hint)
+		}
+		if c.Status == handlers.CheckFail {
+			code = exitError
+		}
+	}
+
+	return code
+}
+
+func checkServer() handlers.Check {
+	status, err := data.CheckServer()
+	if err != nil {
+		return handlers.Check{Name: "server", Status: handlers.CheckFail, Detail: err.Error(), Hint: fmt.Sprintf("check network, proxy and TLS settings for %s", data.ServerAddr())}
+	}
+
+	detail := fmt.Sprintf("%s responded %d", data.ServerAddr(), status)
+	switch true {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return handlers.Check{Name: "server", Status: handlers.CheckFail, Detail: detail, Hint: "api key rejected, check API_KEY, API_KEY_FILE or API_KEY_COMMAND"}
+	case status == http.StatusNotFound || status == http.StatusMethodNotAllowed:
+		return handlers.Check{Name: "server", Status: handlers.CheckWarn, Detail: detail, Hint: "server reachable but has no auth check, api key not verified"}
+	case status >= 300:
+		return handlers.Check{Name: "server", Status: handlers.CheckFail, Detail: detail, Hint: "server reachable but not accepting records, check server logs"}
+	default:
+		return handlers.Check{Name: "server", Status: handlers.CheckPass, Detail: detail + ", api key accepted"}
+	}
+}
@@ -1,89 +1,133 @@
 package main
 
 import (
-	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
-	"os/signal"
-	"runtime"
-	"syscall"
-	"time"
+)
 
-	"github.com/edgestats/edgestats-client/handlers"
-	"github.com/fsnotify/fsnotify"
+const (
+	exitOK    = 0 // success
+	exitError = 1 // runtime or config error
+	exitUsage = 2 // bad command line
 )
 
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string) int
+}
+
+var commands []command
+
+func init() {
+	// assigned in init, help refers back to commands
+	commands = []command{
+		{"run", "run", "Watch the edge node log and send stats to the server (default)", runCmd},
+		{"status", "status [--addr host:port]", "Show pipeline, node and client status of a running client", statusCmd},
+		{"validate", "validate <logfile>", "Parse a log file and print what would be sent, nothing is sent", validateCmd},
+		{"replay", "replay [--from offset] <logfile>", "Parse a log file once and send its records, ie backfill", replayCmd},
+		{"report", "report [--schedule file] [logfile]", "Estimate participation and rewards from votes in the current and rotated log", reportCmd},
+		{"version", "version", "Print client version, commit and configured server", versionCmd},
+		{"quarantine", "quarantine [--dry-run] <show|retry>", "Show lines parsers failed on, or re-run them after a parser fix", quarantineCmd},
+		{"doctor", "doctor [--wait 10s]", "Check log file, file events, log lines, bootstrap and server for problems", doctorCmd},
+		{"config", "config <show|check>", "Show effective configuration or check it for errors", configCmd},
+		{"help", "help [command]", "Show help for a command", helpCmd},
+	}
+}
+
 func main() {
-	fmt.Println("Initializing EdgeStats...")
-	fmt.Printf("System version: %s/%s\n", runtime.GOOS, runtime.GOARCH)
-	fmt.Printf("Golang version: %s\n", runtime.Version())
-
-	fp, err := handlers.GetFilePath(runtime.GOOS)
-	if err != nil {
-		fmt.Println("Error initializing:", err)
-		os.Exit(1)
+	os.Exit(dispatch(os.Args[1:]))
+}
+
+func dispatch(args []string) int {
+	// no command runs the client, as before subcommands
+	if len(args) == 0 {
+		return runCmd(nil)
 	}
 
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		fmt.Println("Error initializing:", err)
-		os.Exit(1)
+	switch args[0] {
+	case "-h", "-help", "--help":
+		usage(os.Stdout)
+		return exitOK
 	}
-	defer watcher.Close()
 
-	if err := watcher.Add(fp); err != nil {
-		fmt.Println("Error initializing: no file", fp)
-		os.Exit(1)
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:])
+		}
 	}
 
-	fmt.Println("EdgeStats watching file", fp)
-	fmt.Println("EdgeStats is ready...")
-
-	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
-
-	var offset int64
-
-	go func() {
-		for {
-			select {
-			case event, ok := <-watcher.Events:
-				if !ok {
-					return
-				}
-				// process event
-				offset, err = handlers.ProcessEvent(watcher, event, fp, offset)
-				if err != nil {
-					continue // perhaps log to log file
-				}
-			case error, ok := <-watcher.Errors:
-				if !ok {
-					return
-				}
-				fmt.Println("error: ", error) // perhaps log to log file
+	fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", args[0])
+	usage(os.Stderr)
+	return exitUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: edgestats-client <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'edgestats-client help <command>' for command flags.")
+	fmt.Fprintln(w, "Exit codes: 0 success, 1 error, 2 usage.")
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		for _, c := range commands {
+			if c.name != name {
+				continue
 			}
+			fmt.Fprintf(fs.Output(), "Usage: edgestats-client %s\n\n%s\n", c.usage, c.summary)
 		}
-	}()
-
-	// goroutine to poke log file at interval
-	// needed for windows & perhaps other OSs
-	go func() {
-		ticker := time.NewTicker(6000 * time.Millisecond)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ticker.C:
-				if err := handlers.PokeFilePath(fp); err != nil {
-					continue // perhaps log to log file
-				}
-			}
+		var n int
+		fs.VisitAll(func(*flag.Flag) { n++ })
+		if n > 0 {
+			fmt.Fprintln(fs.Output(), "\nFlags:")
+			fs.PrintDefaults()
 		}
-	}()
+	}
+	return fs
+}
+
+func parseFlags(fs *flag.FlagSet, args []string, nargs int) (int, bool) {
+	// returns exit code and whether command should stop
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK, true
+		}
+		return exitUsage, true
+	}
+
+	if nargs >= 0 && fs.NArg() != nargs {
+		fmt.Fprintf(fs.Output(), "Expected %d argument(s), got %d\n\n", nargs, fs.NArg())
+		fs.Usage()
+		return exitUsage, true
+	}
+
+	return exitOK, false
+}
 
-	sig := <-ch
-	fmt.Printf("\nRecieved %s signal, shutting down...\n", sig)
+func helpCmd(args []string) int {
+	if len(args) == 0 {
+		usage(os.Stdout)
+		return exitOK
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run([]string{"-h"})
+		}
+	}
 
-	_, cancel := context.WithTimeout(context.Background(), 6*time.Second)
-	defer cancel()
+	fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", args[0])
+	usage(os.Stderr)
+	return exitUsage
 }
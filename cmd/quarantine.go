@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/edgestats/edgestats-client/data"
+	"github.com/edgestats/edgestats-client/handlers"
+)
+
+func quarantineCmd(args []string) int {
+	fs := newFlagSet("quarantine")
+	dry := fs.Bool("dry-run", false, "print what retry would send, the quarantine file is left as is")
+	if code, stop := parseFlags(fs, args, 1); stop {
+		return code
+	}
+
+	if err := data.SetUnparsedFile(os.Getenv("UNPARSED_FILEPATH")); err != nil {
+		fmt.Println("Error initializing:", err)
+		return exitError
+	}
+	fp := data.UnparsedFile()
+
+	switch fs.Arg(0) {
+	case "show":
+		return showUnparsed(fp)
+	case "retry":
+		return retryUnparsed(fp, *dry)
+	default:
+		fmt.Fprintf(fs.Output(), "Unknown quarantine action: %s\n\n", fs.Arg(0))
+		fs.Usage()
+		return exitUsage
+	}
+}
+
+func showUnparsed(fp string) int {
+	ls, err := data.ReadUnparsed(fp)
+	if err != nil {
+		fmt.Println("Error reading quarantine:", err)
+		return exitError
+	}
+
+	fmt.Printf("Quarantine file: %s\n", fp)
+	for _, r := range data.CountUnparsed(ls) {
+		fmt.Printf("%6d  [%s] %s\n", r.Count, r.Parser, r.Reason)
+	}
+	fmt.Printf("Quarantined lines: %v\n", len(ls))
+
+	return exitOK
+}
+
+func retryUnparsed(fp string, dry bool) int {
+	// parse as run would, sending needs full config
+	var err error
+	if dry {
+		err = setupLog()
+	} else {
+		_, err = setup()
+	}
+	if err != nil {
+		fmt.Println("Error initializing:", err)
+		return exitError
+	}
+
+	// quarantined lines may predate the node address
+	if lf, err := handlers.GetFilePath(runtime.GOOS); err == nil {
+		bootstrap(lf)
+	}
+
+	rs, err := handlers.RetryUnparsed(fp, !dry, func(r handlers.LineResult) {
+		if dry {
+			handlers.PrintResult(os.Stdout, r)
+		}
+	})
+	if err != nil {
+		fmt.Println("Error retrying:", err)
+		return exitError
+	}
+
+	fmt.Printf("Retry - lines: %v, fixed: %v, unmatched: %v, still failing: %v\n", rs.Lines, rs.Fixed, rs.Unmatched, rs.Failed)
+
+	return exitOK
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/edgestats/edgestats-client/data"
+	"github.com/edgestats/edgestats-client/handlers"
+)
+
+func replayCmd(args []string) int {
+	fs := newFlagSet("replay")
+	from := fs.Int64("from", 0, "byte offset to start reading at")
+	if code, stop := parseFlags(fs, args, 1); stop {
+		return code
+	}
+	fp := fs.Arg(0)
+
+	sched, err := setup()
+	if err != nil {
+		fmt.Println("Error initializing:", err)
+		return exitError
+	}
+
+	bootstrap(fp)
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go sched.Run(ctx)
+
+	// pipeline waits on scheduler, ie no records dropped on full queue
+	pl := handlers.NewPipeline(0)
+	handlers.SetPipeline(pl)
+	plDone := make(chan struct{})
+	go func() {
+		pl.Run(ctx)
+		close(plDone)
+	}()
+
+	offset, err := handlers.ReplayLog(fp, *from)
+	pl.Close()
+	<-plDone
+	if err != nil {
+		fmt.Println("Error replaying:", err)
+		return exitError
+	}
+
+	// send held back incidents and queued records
+	fctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	data.FlushIncidents(fctx)
+	stop()
+
+	if err := sched.Flush(fctx); err != nil {
+		fmt.Println("Error replaying:", err)
+		return exitError
+	}
+
+	for _, s := range pl.Stats() {
+		fmt.Printf("Pipeline %s - in: %v, out: %v, dropped: %v, errors: %v\n", s.Name, s.In, s.Out, s.Dropped, s.Errors)
+	}
+	fmt.Println("Replayed to offset", offset)
+
+	return exitOK
+}
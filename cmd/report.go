@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/edgestats/edgestats-client/data"
+	"github.com/edgestats/edgestats-client/handlers"
+)
+
+func reportCmd(args []string) int {
+	fs := newFlagSet("report")
+	schedule := fs.String("schedule", "", "reward schedule file, defaults to REWARD_SCHEDULE_FILE")
+	if code, stop := parseFlags(fs, args, -1); stop {
+		return code
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	if *schedule != "" {
+		os.Setenv("REWARD_SCHEDULE_FILE", *schedule)
+	}
+	if err := setupLog(); err != nil {
+		fmt.Println("Error initializing:", err)
+		return exitError
+	}
+
+	fp := fs.Arg(0)
+	if fp == "" {
+		var err error
+		if fp, err = handlers.GetFilePath(runtime.GOOS); err != nil {
+			fmt.Println("Error initializing:", err)
+			return exitError
+		}
+	}
+
+	// votes from rotated then current log, nothing is sent
+	if _, err := handlers.Bootstrap(fp); err != nil {
+		fmt.Println("Error reading log:", err)
+		return exitError
+	}
+	for _, f := range handlers.LogHistory(fp) {
+		if _, err := handlers.ValidateLog(f, nil); err != nil {
+			fmt.Println("Error reading log:", err)
+			return exitError
+		}
+	}
+
+	est := data.RewardStatus().(data.RewardEstimate)
+	if est.Votes == 0 {
+		fmt.Println("No votes found in", fp)
+		return exitError
+	}
+
+	fmt.Printf("Node address:     %s\n", est.Addr)
+	fmt.Printf("Votes:            %d (heights %d-%d)\n", est.Votes, est.FromHeight, est.ToHeight)
+	fmt.Printf("Block time:       %.2fs\n", est.BlockSeconds)
+	if est.ScheduleError != "" {
+		fmt.Printf("Votes per day:    %.1f\n", est.VotesPerDay)
+		fmt.Printf("Rewards:          not estimated, %s\n", est.ScheduleError)
+		return exitOK
+	}
+	fmt.Printf("Expected votes:   %d\n", est.Expected)
+	fmt.Printf("Participation:    %.1f%%\n", est.Participation*100)
+	fmt.Printf("Votes per day:    %.1f\n", est.VotesPerDay)
+	fmt.Printf("Rewards per day:  %.4f %s\n", est.RewardsPerDay, est.Token)
+	fmt.Printf("Rewards per week: %.4f %s\n", est.RewardsPerWeek, est.Token)
+
+	return exitOK
+}
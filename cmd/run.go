@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/edgestats/edgestats-client/data"
+	"github.com/edgestats/edgestats-client/handlers"
+	"github.com/fsnotify/fsnotify"
+)
+
+func runCmd(args []string) int {
+	fs := newFlagSet("run")
+	dry := fs.Bool("dry-run", false, "print records with source line instead of sending, server is not contacted")
+	if code, stop := parseFlags(fs, args, 0); stop {
+		return code
+	}
+
+	fmt.Println("Initializing EdgeStats...")
+	fmt.Printf("Client version: %s\n", data.Version())
+	fmt.Printf("System version: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Printf("Golang version: %s\n", runtime.Version())
+
+	fp, err := handlers.GetFilePath(runtime.GOOS)
+	if err != nil {
+		fmt.Println("Error initializing:", err)
+		return exitError
+	}
+
+	// dry run only needs log parsing config
+	var sched *data.Scheduler
+	if *dry {
+		err = setupLog()
+	} else {
+		sched, err = setup()
+	}
+	if err != nil {
+		fmt.Println("Error initializing:", err)
+		return exitError
+	}
+
+	bootstrap(fp)
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	if sched != nil {
+		go sched.Run(ctx)
+	}
+
+	pl, plDone := startPipeline(ctx, *dry)
+
+	wd, err := startBackground(ctx, pl, fp, *dry)
+	if err != nil {
+		fmt.Println("Error initializing:", err)
+		return exitError
+	}
+	serveStatus(ctx, pl, wd, fp)
+
+	watcher, err := watchLog(fp)
+	if err != nil {
+		fmt.Println("Error initializing:", err)
+		return exitError
+	}
+	defer watcher.Close()
+
+	fmt.Println("EdgeStats watching file", fp)
+	fmt.Println("EdgeStats is ready...")
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
+
+	sig := <-ch
+	fmt.Printf("\nRecieved %s signal, shutting down...\n", sig)
+
+	shutdown(pl, plDone, sched, stop, *dry)
+
+	return exitOK
+}
+
+func startPipeline(ctx context.Context, dry bool) (*handlers.Pipeline, chan struct{}) {
+	pl := handlers.NewPipeline(0)
+	if dry {
+		fmt.Println("EdgeStats dry run, records are printed not sent")
+		pl.SetDryRun(os.Stdout)
+	}
+	handlers.SetPipeline(pl)
+
+	done := make(chan struct{})
+	go func() {
+		pl.Run(ctx)
+		close(done)
+	}()
+
+	return pl, done
+}
+
+func startBackground(ctx context.Context, pl *handlers.Pipeline, fp string, dry bool) (*handlers.Watchdog, error) {
+	// report events to stdout and server
+	data.AddSink(data.PrintSink{})
+	if !dry {
+		data.AddSink(data.ServerSink{})
+	}
+
+	wd, err := handlers.WatchdogFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	handlers.SetWatchdog(wd)
+	go wd.Run(ctx, 10*time.Second)
+	go data.RunPeerHistory(ctx)
+
+	hb, err := data.HeartbeatIntervalFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	// register client in background, ie unreachable server does not delay tailing
+	// heartbeat retries on failure
+	if !dry {
+		go func() {
+			if err := data.Register(); err != nil {
+				fmt.Println("Warning: client not registered:", err)
+			}
+			data.RunHeartbeat(ctx, hb, func() int {
+				return pl.Depth() + data.QueueLen()
+			})
+		}()
+	}
+
+	// forward warning/error incidents held back by rate limit
+	iw, err := data.IncidentWindowFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	data.SetIncidentWindow(iw)
+	if !dry {
+		go data.RunIncidents(ctx, iw)
+	}
+
+	return wd, nil
+}
+
+func serveStatus(ctx context.Context, pl *handlers.Pipeline, wd *handlers.Watchdog, fp string) {
+	// local status api, ie for status command
+	handlers.RegisterStatus("pipeline", func() interface{} { return pl.Stats() })
+	handlers.RegisterStatus("node", wd.Status)
+	handlers.RegisterStatus("address", data.AddressStatus)
+	handlers.RegisterStatus("peers", data.PeerStatus)
+	handlers.RegisterStatus("consensus", data.ConsensusStatus)
+	handlers.RegisterStatus("rewards", data.RewardStatus)
+	handlers.RegisterStatus("unparsed", data.UnparsedStatus)
+	handlers.RegisterStatus("client", func() interface{} {
+		return map[string]interface{}{
+			"version":     data.Version(),
+			"log_file":    fp,
+			"last_log_at": data.LastLogTime(),
+			"queue_depth": pl.Depth() + data.QueueLen(),
+		}
+	})
+	if addr := handlers.GetStatusAddr(); addr != "off" {
+		go func() {
+			if err := handlers.ServeStatus(ctx, addr); err != nil {
+				fmt.Println("Warning: status api not available:", err)
+			}
+		}()
+	}
+}
+
+func watchLog(fp string) (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := watcher.Add(fp); err != nil {
+		watcher.Close()
+		s := fmt.Sprintf("no file %s", fp)
+		return nil, errors.New(s)
+	}
+
+	var offset int64
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				// process event
+				var err error
+				offset, err = handlers.ProcessEvent(watcher, event, fp, offset)
+				if err != nil {
+					continue // perhaps log to log file
+				}
+			case error, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				fmt.Println("error: ", error) // perhaps log to log file
+			}
+		}
+	}()
+
+	// goroutine to poke log file at interval
+	// needed for windows & perhaps other OSs
+	go func() {
+		ticker := time.NewTicker(6000 * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := handlers.PokeFilePath(fp); err != nil {
+					continue // perhaps log to log file
+				}
+			}
+		}
+	}()
+
+	return watcher, nil
+}
+
+func shutdown(pl *handlers.Pipeline, plDone chan struct{}, sched *data.Scheduler, stop context.CancelFunc, dry bool) {
+	// drain pipeline and send queued records before exit
+	fctx, cancel := context.WithTimeout(context.Background(), 6*time.Second)
+	defer cancel()
+
+	pl.Close()
+	select {
+	case <-plDone:
+	case <-fctx.Done():
+	}
+	if !dry {
+		data.FlushIncidents(fctx)
+	}
+	stop()
+
+	if sched != nil {
+		if err := sched.Flush(fctx); err != nil {
+			fmt.Println("error: ", err) // perhaps log to log file
+		}
+	}
+
+	for _, s := range pl.Stats() {
+		fmt.Printf("Pipeline %s - in: %v, out: %v, dropped: %v, errors: %v\n", s.Name, s.In, s.Out, s.Dropped, s.Errors)
+	}
+}
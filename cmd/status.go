@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/edgestats/edgestats-client/handlers"
+)
+
+func statusCmd(args []string) int {
+	fs := newFlagSet("status")
+	addr := fs.String("addr", handlers.GetStatusAddr(), "status api address of the running client")
+	if code, stop := parseFlags(fs, args, 0); stop {
+		return code
+	}
+
+	if *addr == "off" {
+		fmt.Println("Status api disabled, set STATUS_ADDR or --addr")
+		return exitError
+	}
+
+	// query local status api of a running client
+	c := &http.Client{Timeout: 5 * time.Second}
+	resp, err := c.Get(fmt.Sprintf("http://%s/status", *addr))
+	if err != nil {
+		fmt.Println("Client not reachable:", err)
+		return exitError
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		fmt.Println("Error reading status:", err)
+		return exitError
+	}
+	if resp.StatusCode != http.StatusOK {
+		fmt.Printf("Unexpected status: %d\n", resp.StatusCode)
+		return exitError
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, b, "", "  "); err != nil {
+		fmt.Println("Error reading status:", err)
+		return exitError
+	}
+	fmt.Println(out.String())
+
+	return exitOK
+}
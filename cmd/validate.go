@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/edgestats/edgestats-client/handlers"
+)
+
+func validateCmd(args []string) int {
+	fs := newFlagSet("validate")
+	if code, stop := parseFlags(fs, args, 1); stop {
+		return code
+	}
+
+	// parse as run would, log times per env
+	if err := setupLog(); err != nil {
+		fmt.Println("Error initializing:", err)
+		return exitError
+	}
+
+	// parse only, nothing is sent
+	vs, err := handlers.ValidateLog(fs.Arg(0), func(r handlers.LineResult) {
+		handlers.PrintResult(os.Stdout, r)
+	})
+	if err != nil {
+		fmt.Println("Error validating:", err)
+		return exitError
+	}
+
+	fmt.Printf("Validate - lines: %v, matched: %v, valid: %v, sampled: %v, errors: %v\n", vs.Lines, vs.Matched, vs.Valid, vs.Sampled, vs.Errors)
+
+	return exitOK
+}
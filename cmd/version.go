@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/edgestats/edgestats-client/data"
+)
+
+func versionCmd(args []string) int {
+	fs := newFlagSet("version")
+	if code, stop := parseFlags(fs, args, 0); stop {
+		return code
+	}
+
+	fmt.Printf("Client version: %s\n", data.Version())
+	fmt.Printf("Client commit:  %s\n", data.Commit())
+	fmt.Printf("Server address: %s\n", data.ServerAddr())
+	fmt.Printf("System version: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Printf("Golang version: %s\n", runtime.Version())
+
+	return exitOK
+}
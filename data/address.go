@@ -0,0 +1,120 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	addrMismatchEvent = "address_mismatch"
+)
+
+var (
+	errAddrMismatch = errors.New("address mismatch")
+)
+
+var (
+	addrMu     sync.RWMutex // guards configured address state below
+	configAddr string
+	mismatches map[string]*AddrMismatch
+)
+
+type AddrMismatch struct {
+	Configured string    `json:"configured"`
+	Reported   string    `json:"reported"`
+	Height     int       `json:"height"`
+	Count      int       `json:"count"`
+	FirstAt    time.Time `json:"first_at"`
+	LastAt     time.Time `json:"last_at"`
+}
+
+type AddrStatus struct {
+	Configured string          `json:"configured,omitempty"`
+	Address    string          `json:"address"`
+	Mismatches []*AddrMismatch `json:"mismatches,omitempty"`
+}
+
+func SetNodeAddress(addr string) error {
+	// empty learns address from votes
+	addr = strings.ToLower(strings.TrimSpace(addr))
+	if addr != "" && !isHexAddr(addr) {
+		s := fmt.Sprintf("invalid NODE_ADDRESS: %s", addr)
+		return errors.New(s)
+	}
+
+	addrMu.Lock()
+	configAddr = addr
+	mismatches = make(map[string]*AddrMismatch)
+	addrMu.Unlock()
+
+	// known from first line, ie no bootstrap needed
+	if addr != "" {
+		return setAddr(addr)
+	}
+
+	return nil
+}
+
+func AddressStatus() interface{} {
+	addrMu.RLock()
+	defer addrMu.RUnlock()
+
+	as := AddrStatus{Configured: configAddr, Address: getAddr()}
+	for _, m := range mismatches {
+		c := *m
+		as.Mismatches = append(as.Mismatches, &c)
+	}
+
+	return as
+}
+
+func IsAddrMismatch(err error) bool {
+	return err == errAddrMismatch
+}
+
+func checkAddr(va string, vh int, t time.Time) error {
+	addrMu.Lock()
+	if configAddr == "" {
+		addrMu.Unlock()
+		return setAddr(va)
+	}
+	if va == configAddr {
+		addrMu.Unlock()
+		return nil
+	}
+
+	// vote from other address, ie re-keyed node or wrong log
+	m, seen := mismatches[va]
+	if !seen {
+		m = &AddrMismatch{Configured: configAddr, Reported: va, FirstAt: t}
+		mismatches[va] = m
+	}
+	m.Height = vh
+	m.Count++
+	m.LastAt = t
+	alert := *m
+	addrMu.Unlock()
+
+	// alert once per reported address
+	if !seen {
+		PublishEvent(NewEvent(addrMismatchEvent, alert))
+	}
+
+	return errAddrMismatch
+}
+
+func isHexAddr(addr string) bool {
+	if len(addr) < 3 || !strings.HasPrefix(addr, "0x") {
+		return false
+	}
+	for _, c := range addr[2:] {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+
+	return true
+}
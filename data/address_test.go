@@ -0,0 +1,73 @@
+package data
+
+import (
+	"testing"
+)
+
+func TestSetNodeAddress(t *testing.T) {
+	defer ResetNode()
+	defer SetNodeAddress("")
+
+	// test invalid address
+	if err := SetNodeAddress("8d25fa2e7d"); err == nil {
+		t.Fatalf("data.SetNodeAddress() returned: nil, wanted error")
+	}
+	if err := SetNodeAddress("0xZZ"); err == nil {
+		t.Fatalf("data.SetNodeAddress() returned: nil, wanted error")
+	}
+
+	// test configured address known before any vote
+	ResetNode()
+	if err := SetNodeAddress(" 0x8D25FA2E7D "); err != nil {
+		t.Fatalf("data.SetNodeAddress() returned error: %v", err)
+	}
+	if got := getAddr(); got != "0x8d25fa2e7d" {
+		t.Fatalf("data.SetNodeAddress() set: %v, wanted: %v", got, "0x8d25fa2e7d")
+	}
+
+	// test kept on reset
+	ResetNode()
+	if got := getAddr(); got != "0x8d25fa2e7d" {
+		t.Fatalf("data.ResetNode() kept: %v, wanted: %v", got, "0x8d25fa2e7d")
+	}
+}
+
+func TestCheckAddr(t *testing.T) {
+	// setup test variables
+	var sink = &testSink{}
+
+	AddSink(sink)
+	defer ResetSinks()
+	defer ResetNode()
+	defer SetNodeAddress("")
+
+	// test learned from votes when not configured
+	SetNodeAddress("")
+	setPeers(16, 16)
+	if err := NewUMBroadcast().Parse(UMBroadcastedEx); err != nil {
+		t.Fatalf("data.UMBroadcastParse() returned error: %v", err)
+	}
+
+	// test configured address matching vote
+	SetNodeAddress("0x8d25fa2e7d")
+	if err := NewUMBroadcast().Parse(UMBroadcastedEx); err != nil {
+		t.Fatalf("data.UMBroadcastParse() returned error: %v", err)
+	}
+
+	// test mismatch dropped, alerted once
+	SetNodeAddress("0x0000aaaa")
+	for i := 0; i < 2; i++ {
+		if err := NewUMBroadcast().Parse(UMBroadcastedEx); !IsAddrMismatch(err) {
+			t.Fatalf("data.UMBroadcastParse() returned: %v, wanted error: %v", err, errAddrMismatch)
+		}
+	}
+
+	if len(sink.events) != 1 || sink.events[0].Type != addrMismatchEvent || sink.events[0].Addr != "0x0000aaaa" {
+		t.Fatalf("data.checkAddr() published: %+v", sink.events)
+	}
+
+	as := AddressStatus().(AddrStatus)
+	if as.Configured != "0x0000aaaa" || len(as.Mismatches) != 1 || as.Mismatches[0].Count != 2 || as.Mismatches[0].Reported != "0x8d25fa2e7d" {
+		t.Fatalf("data.AddressStatus() returned: %+v", as)
+	}
+}
@@ -0,0 +1,234 @@
+package data
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	commandKeyTTL = 5 * time.Minute
+	devAPIKey     = "devkey" // placeholder unless set via ldflags
+)
+
+var (
+	credentials    CredentialProvider = staticKey{}
+	errEmptyAPIKey                    = errors.New("empty api key")
+	errNoAPIKey                       = errors.New("no api key, set API_KEY_FILE, API_KEY_COMMAND or API_KEY, or build with -ldflags")
+	errBadCommand                     = errors.New("unterminated quote or escape in api key command")
+)
+
+type CredentialProvider interface {
+	APIKey() (string, error)
+}
+
+type staticKey struct{}
+
+type envKey struct {
+	name string
+}
+
+type fileKey struct {
+	mu      sync.Mutex
+	fp      string
+	key     string
+	modTime time.Time
+	size    int64
+}
+
+type commandKey struct {
+	mu      sync.Mutex
+	args    []string
+	key     string
+	fetched time.Time
+}
+
+func (staticKey) APIKey() (string, error) {
+	// compiled in via ldflags
+	if apiKey == "" {
+		return "", errEmptyAPIKey
+	}
+	return apiKey, nil
+}
+
+func (k envKey) APIKey() (string, error) {
+	v := strings.TrimSpace(os.Getenv(k.name))
+	if v == "" {
+		return v, errEmptyAPIKey
+	}
+	return v, nil
+}
+
+func NewFileKey(fp string) (CredentialProvider, error) {
+	k := &fileKey{fp: fp}
+	if _, err := k.APIKey(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (k *fileKey) APIKey() (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	info, err := os.Stat(k.fp)
+	if err != nil {
+		return "", err
+	}
+
+	// reload when file replaced or rewritten
+	if k.key != "" && info.ModTime().Equal(k.modTime) && info.Size() == k.size {
+		return k.key, nil
+	}
+
+	if err := checkKeyFileMode(k.fp, info); err != nil {
+		return "", err
+	}
+
+	b, err := os.ReadFile(k.fp)
+	if err != nil {
+		return "", err
+	}
+	v := string(bytes.TrimSpace(b))
+	if v == "" {
+		return "", errEmptyAPIKey
+	}
+
+	k.key = v
+	k.modTime = info.ModTime()
+	k.size = info.Size()
+
+	return k.key, nil
+}
+
+func NewCommandKey(cmd string) (CredentialProvider, error) {
+	// ie "secret-tool lookup service edgestats" or "security find-generic-password -s 'edge stats' -w"
+	args, err := splitCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &commandKey{args: args}, nil
+}
+
+func (k *commandKey) APIKey() (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.key != "" && time.Since(k.fetched) < commandKeyTTL {
+		return k.key, nil
+	}
+	if len(k.args) == 0 {
+		return "", errEmptyAPIKey
+	}
+
+	out, err := exec.Command(k.args[0], k.args[1:]...).Output()
+	if err != nil {
+		return "", err
+	}
+	v := string(bytes.TrimSpace(out))
+	if v == "" {
+		return "", errEmptyAPIKey
+	}
+
+	k.key = v
+	k.fetched = time.Now()
+
+	return k.key, nil
+}
+
+func CredentialsFromEnv() (CredentialProvider, error) {
+	// prefer secrets file, then keyring command, then env, then ldflags
+	switch true {
+	case os.Getenv("API_KEY_FILE") != "":
+		return NewFileKey(os.Getenv("API_KEY_FILE"))
+	case os.Getenv("API_KEY_COMMAND") != "":
+		return NewCommandKey(os.Getenv("API_KEY_COMMAND"))
+	case os.Getenv("API_KEY") != "":
+		return envKey{name: "API_KEY"}, nil
+	case apiKey == "" || apiKey == devAPIKey:
+		// refuse placeholder key, ie build without -ldflags
+		return nil, errNoAPIKey
+	default:
+		return staticKey{}, nil
+	}
+}
+
+func SetCredentialProvider(p CredentialProvider) {
+	credentials = p
+}
+
+func checkKeyFileMode(fp string, info os.FileInfo) error {
+	// refuse secrets readable by group or others, ie like ssh keys
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	if mode := info.Mode().Perm(); mode&0077 != 0 {
+		s := fmt.Sprintf("api key file %s permissions %#o too open, want 0600", fp, mode)
+		return errors.New(s)
+	}
+
+	return nil
+}
+
+func splitCommand(s string) ([]string, error) {
+	// split words like a posix shell, ie single and double quotes and backslash escapes
+	var args []string
+	var word strings.Builder
+	var inWord bool
+	var quote rune
+	var escaped bool
+
+	for _, c := range s {
+		switch true {
+		case escaped:
+			// inside double quotes backslash only escapes a few chars
+			if quote == '"' && !strings.ContainsRune("\\\"$`", c) {
+				word.WriteRune('\\')
+			}
+			word.WriteRune(c)
+			escaped = false
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '\\':
+			escaped = true
+			inWord = true
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+
+	if escaped || quote != 0 {
+		return nil, errBadCommand
+	}
+	if inWord {
+		args = append(args, word.String())
+	}
+
+	return args, nil
+}
@@ -0,0 +1,154 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestFileKey(t *testing.T) {
+	// setup test variables
+	var fp = filepath.Join(t.TempDir(), "apikey")
+
+	_ = os.WriteFile(fp, []byte("thetaverse\n"), 0600)
+
+	// test key loaded and trimmed
+	k, err := NewFileKey(fp)
+	if err != nil {
+		t.Fatalf("data.NewFileKey() returned error: %v", err)
+	}
+
+	if got, _ := k.APIKey(); got != "thetaverse" {
+		t.Fatalf("data.fileKey.APIKey() returned: %v, wanted: %v", got, "thetaverse")
+	}
+
+	// test key reloaded once file changes
+	_ = os.WriteFile(fp, []byte("rotatedkey"), 0600)
+	later := time.Now().Add(time.Minute)
+	_ = os.Chtimes(fp, later, later)
+
+	if got, _ := k.APIKey(); got != "rotatedkey" {
+		t.Fatalf("data.fileKey.APIKey() returned: %v, wanted: %v", got, "rotatedkey")
+	}
+
+	// test empty file
+	_ = os.WriteFile(fp, []byte(" \n"), 0600)
+	_ = os.Chtimes(fp, later.Add(time.Minute), later.Add(time.Minute))
+	if got, err := k.APIKey(); err == nil {
+		t.Fatalf("data.fileKey.APIKey() returned: %v, wanted error", got)
+	}
+
+	// test missing file
+	if _, err := NewFileKey(filepath.Join(t.TempDir(), "none")); err == nil {
+		t.Fatalf("data.NewFileKey() returned: %v, wanted error", err)
+	}
+}
+
+func TestFileKeyMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes not checked on windows")
+	}
+
+	// test group readable file refused
+	fp := filepath.Join(t.TempDir(), "apikey")
+	_ = os.WriteFile(fp, []byte("thetaverse"), 0644)
+	_ = os.Chmod(fp, 0644)
+
+	if _, err := NewFileKey(fp); err == nil {
+		t.Fatalf("data.NewFileKey() returned: %v, wanted error", err)
+	}
+}
+
+func TestCommandKey(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("echo not an executable on windows")
+	}
+
+	// test key read from command output
+	k, _ := NewCommandKey("echo keyringkey")
+	if got, err := k.APIKey(); err != nil || got != "keyringkey" {
+		t.Fatalf("data.commandKey.APIKey() returned: %v, %v, wanted: %v", got, err, "keyringkey")
+	}
+
+	// test quoted argument kept as one word
+	k, _ = NewCommandKey(`echo 'keyring  key'`)
+	if got, err := k.APIKey(); err != nil || got != "keyring  key" {
+		t.Fatalf("data.commandKey.APIKey() returned: %v, %v, wanted: %v", got, err, "keyring  key")
+	}
+
+	// test failing command
+	k, _ = NewCommandKey("false")
+	if got, err := k.APIKey(); err == nil {
+		t.Fatalf("data.commandKey.APIKey() returned: %v, wanted error", got)
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	// setup test variables
+	tests := []struct {
+		cmd  string
+		want []string
+	}{
+		{"secret-tool lookup service edgestats", []string{"secret-tool", "lookup", "service", "edgestats"}},
+		{`security find-generic-password -s 'edge stats' -w`, []string{"security", "find-generic-password", "-s", "edge stats", "-w"}},
+		{`pass show "edge stats/api key"`, []string{"pass", "show", "edge stats/api key"}},
+		{`cat /keys/edge\ stats ''`, []string{"cat", "/keys/edge stats", ""}},
+		{`echo "a\"b\c"`, []string{"echo", `a"b\c`}},
+		{"  ", nil},
+	}
+
+	for _, tc := range tests {
+		got, err := splitCommand(tc.cmd)
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("data.splitCommand(%q) returned: %q, %v, wanted: %q", tc.cmd, got, err, tc.want)
+		}
+	}
+
+	// test unterminated quote and escape
+	for _, cmd := range []string{`echo 'key`, `echo "key`, `echo key\`} {
+		if got, err := splitCommand(cmd); err != errBadCommand {
+			t.Fatalf("data.splitCommand(%q) returned: %q, %v, wanted: %v", cmd, got, err, errBadCommand)
+		}
+	}
+}
+
+func TestCredentialsFromEnv(t *testing.T) {
+	defer os.Unsetenv("API_KEY")
+	defer os.Unsetenv("API_KEY_FILE")
+
+	// test placeholder key refused
+	if p, err := CredentialsFromEnv(); err != errNoAPIKey {
+		t.Fatalf("data.CredentialsFromEnv() returned: %v, %v, wanted: %v", p, err, errNoAPIKey)
+	}
+
+	// test ldflags key by default
+	defer func(k string) { apiKey = k }(apiKey)
+	apiKey = "buildkey"
+	p, err := CredentialsFromEnv()
+	if err != nil {
+		t.Fatalf("data.CredentialsFromEnv() returned error: %v", err)
+	}
+
+	if got, _ := p.APIKey(); got != "buildkey" {
+		t.Fatalf("data.CredentialsFromEnv() returned key: %v, wanted: %v", got, "buildkey")
+	}
+
+	// test env key
+	os.Setenv("API_KEY", "envkey")
+	p, _ = CredentialsFromEnv()
+	if got, _ := p.APIKey(); got != "envkey" {
+		t.Fatalf("data.CredentialsFromEnv() returned key: %v, wanted: %v", got, "envkey")
+	}
+
+	// test file preferred over env
+	fp := filepath.Join(t.TempDir(), "apikey")
+	_ = os.WriteFile(fp, []byte("filekey"), 0600)
+	os.Setenv("API_KEY_FILE", fp)
+	p, _ = CredentialsFromEnv()
+	if got, _ := p.APIKey(); got != "filekey" {
+		t.Fatalf("data.CredentialsFromEnv() returned key: %v, wanted: %v", got, "filekey")
+	}
+}
@@ -0,0 +1,92 @@
+package data
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+)
+
+var (
+	errNoAddr  = errors.New("no address bootstrapped")
+	errNoPeers = errors.New("no peers bootstrapped")
+)
+
+func IsNotBootstrapped(err error) bool {
+	return err == errNoAddr || err == errNoPeers
+}
+
+func Bootstrapped() bool {
+	addr, _, sp := NodeInfo()
+	return addr != "" && sp > 0
+}
+
+func ResetNode() {
+	// forget address and peers, configured address kept
+	addrMu.RLock()
+	addr := configAddr
+	addrMu.RUnlock()
+
+	setAddr(addr)
+	setPeers(0, 0)
+}
+
+func SeedNode(b []byte) {
+	// lines seen newest first, keep first address and peers found
+	// only address and peers read, ie no records, trackers or events
+	addr, _, sp := NodeInfo()
+
+	switch Filter(b) {
+	case UMFilter:
+		if addr != "" {
+			return
+		}
+		if va := seedAddr(b); va != "" {
+			setAddr(va)
+		}
+	case P2PFilter:
+		if sp != 0 {
+			return
+		}
+		if np, suff, ok := seedPeers(b); ok {
+			t, _ := parseTime(b)
+			updatePeers(np, suff, t)
+		}
+	}
+}
+
+func seedAddr(b []byte) string {
+	// address of a broadcast vote line, empty otherwise
+	if filterUMLogType(b) != umBroadcastedVoteFilter {
+		return ""
+	}
+	for k, v, rest := nextKeyValue(b); k != nil; k, v, rest = nextKeyValue(rest) {
+		if bytes.Equal(k, keyAddress) {
+			if va := strings.ToLower(string(v)); isHexAddr(va) {
+				return va
+			}
+			return ""
+		}
+	}
+
+	return ""
+}
+
+func seedPeers(b []byte) (int, int, bool) {
+	// peer counts of a num peers line
+	if filterP2PLogType(b) != p2pNumPeersFilter {
+		return 0, 0, false
+	}
+
+	var np, sp int
+	for k, v, rest := nextKeyValue(b); k != nil; k, v, rest = nextKeyValue(rest) {
+		n, err := atoi(v)
+		switch true {
+		case bytes.Equal(k, keyNumPeers) && err == nil:
+			np = n
+		case bytes.Equal(k, keySufficientNumPeers) && err == nil:
+			sp = n
+		}
+	}
+
+	return np, sp, sp > 0
+}
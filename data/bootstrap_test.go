@@ -0,0 +1,50 @@
+package data
+
+import "testing"
+
+func TestSeedNode(t *testing.T) {
+	// setup test variables
+	var older = []byte("[2021-08-28 08:00:26.951] [info] [ThetaEdgeLauncher] [2021-08-28 08:00:26]  INFO [uptime miner] Broadcasted vote: EENVote{Block: 0x6d0a, Height: 11758001, Address: 0x0000aaaa, Signature: E1A0, CreationTimestamp: 1630151786}")
+
+	ResetNode()
+	defer ResetNode()
+	before := LastLogTime()
+
+	// test nothing bootstrapped
+	if Bootstrapped() {
+		t.Fatalf("data.Bootstrapped() returned: true, wanted: false")
+	}
+
+	// test newest vote kept, older address ignored
+	SeedNode(UMBroadcastedEx)
+	SeedNode(older)
+	if addr, _, _ := NodeInfo(); addr != "0x8d25fa2e7d" || Bootstrapped() {
+		t.Fatalf("data.SeedNode() set address: %v, wanted: %v", addr, "0x8d25fa2e7d")
+	}
+
+	// test peers complete bootstrap
+	SeedNode(P2PNumPeersEx)
+	if !Bootstrapped() {
+		t.Fatalf("data.Bootstrapped() returned: false, wanted: true")
+	}
+
+	// test seeding leaves log time untouched, ie lines not yet sent
+	if got := LastLogTime(); !got.Equal(before) {
+		t.Fatalf("data.SeedNode() marked log time: %v, wanted: %v", got, before)
+	}
+}
+
+func TestIsNotBootstrapped(t *testing.T) {
+	ResetNode()
+	defer ResetNode()
+
+	// test parse before bootstrap
+	if err := NewP2PNumPeers().Parse(P2PNumPeersEx); !IsNotBootstrapped(err) {
+		t.Fatalf("data.IsNotBootstrapped() returned: false, for: %v", err)
+	}
+
+	// test other errors
+	if IsNotBootstrapped(errNoMatch) {
+		t.Fatalf("data.IsNotBootstrapped() returned: true, for: %v", errNoMatch)
+	}
+}
@@ -0,0 +1,198 @@
+package data
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHeartbeatInterval = 60 * time.Second
+)
+
+var (
+	clientsServiceURL   = fmt.Sprintf("%s/stats/clients", apiAddr)
+	heartbeatServiceURL = fmt.Sprintf("%s/stats/clients/heartbeats", apiAddr)
+	authServiceURL      = fmt.Sprintf("%s/stats/clients/auth", apiAddr)
+	version             = "dev"     // set via ldflags
+	commit              = "unknown" // set via ldflags
+)
+
+var (
+	clientID  = newClientID()
+	startedAt = time.Now().UTC()
+)
+
+var (
+	clientMu       sync.RWMutex // guards client state below
+	registered     bool
+	registeredAddr string // node address sent with registration, empty until known
+	lastLogAt      time.Time
+)
+
+type Registration struct {
+	ClientID  string    `json:"client_id"`
+	Version   string    `json:"version"`
+	Commit    string    `json:"commit"`
+	OS        string    `json:"os"`
+	Arch      string    `json:"arch"`
+	GoVersion string    `json:"go_version"`
+	Addr      string    `json:"address"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+type Heartbeat struct {
+	ClientID   string    `json:"client_id"`
+	Addr       string    `json:"address"`
+	LastLogAt  time.Time `json:"last_log_at"`
+	QueueDepth int       `json:"queue_depth"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func Version() string {
+	return version
+}
+
+func Commit() string {
+	return commit
+}
+
+func HeartbeatIntervalFromEnv() (time.Duration, error) {
+	v := os.Getenv("HEARTBEAT_INTERVAL")
+	if v == "" {
+		return defaultHeartbeatInterval, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		s := fmt.Sprintf("invalid HEARTBEAT_INTERVAL: %s", v)
+		return 0, errors.New(s)
+	}
+
+	return d, nil
+}
+
+func NewRegistration() *Registration {
+	return &Registration{
+		ClientID:  clientID,
+		Version:   version,
+		Commit:    commit,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		GoVersion: runtime.Version(),
+		Addr:      getAddr(),
+		StartedAt: startedAt,
+	}
+}
+
+func NewHeartbeat(depth int) *Heartbeat {
+	clientMu.RLock()
+	defer clientMu.RUnlock()
+
+	return &Heartbeat{
+		ClientID:   clientID,
+		Addr:       getAddr(),
+		LastLogAt:  lastLogAt,
+		QueueDepth: depth,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+func Register() error {
+	// announce client, ie separate from node uptime
+	reg := NewRegistration()
+	b, err := json.Marshal(reg)
+	if err != nil {
+		return err
+	}
+	if err := postData(Job{URL: clientsServiceURL, Body: b}); err != nil {
+		return err
+	}
+
+	clientMu.Lock()
+	registered = true
+	registeredAddr = reg.Addr
+	clientMu.Unlock()
+
+	return nil
+}
+
+func CheckServer() (int, error) {
+	// authenticated request recording nothing, status tells key accepted or not
+	resp, _, err := doRequest(http.MethodGet, Job{URL: authServiceURL}, false)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
+
+func RunHeartbeat(ctx context.Context, interval time.Duration, depth func() int) {
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := sendHeartbeat(depth()); err != nil {
+				continue // perhaps log to log file
+			}
+		}
+	}
+}
+
+func sendHeartbeat(depth int) error {
+	// retry registration until server accepted it, again once address known or changed
+	addr := getAddr()
+	clientMu.RLock()
+	ok := registered && registeredAddr == addr
+	clientMu.RUnlock()
+	if !ok {
+		return Register()
+	}
+
+	b, err := json.Marshal(NewHeartbeat(depth))
+	if err != nil {
+		return err
+	}
+
+	return postData(Job{URL: heartbeatServiceURL, Body: b})
+}
+
+func markLogTime(t time.Time) {
+	clientMu.Lock()
+	defer clientMu.Unlock()
+
+	if t.After(lastLogAt) {
+		lastLogAt = t
+	}
+}
+
+func LastLogTime() time.Time {
+	clientMu.RLock()
+	defer clientMu.RUnlock()
+
+	return lastLogAt
+}
+
+func newClientID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
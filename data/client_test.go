@@ -0,0 +1,169 @@
+package data
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestNewRegistration(t *testing.T) {
+	// sim bootstrap address
+	setAddr("0x8d25fa2e7d")
+
+	got := NewRegistration()
+	if got.ClientID != clientID || got.OS != runtime.GOOS || got.Arch != runtime.GOARCH || got.Addr != "0x8d25fa2e7d" {
+		t.Fatalf("data.NewRegistration() returned: %+v", got)
+	}
+}
+
+func TestSendHeartbeat(t *testing.T) {
+	// setup test variables
+	var paths []string
+	var hb Heartbeat
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/stats/clients/heartbeats" {
+			b, _ := io.ReadAll(r.Body)
+			json.Unmarshal(b, &hb)
+		}
+	}))
+	defer srv.Close()
+
+	cu, hu := clientsServiceURL, heartbeatServiceURL
+	clientsServiceURL, heartbeatServiceURL = srv.URL+"/stats/clients", srv.URL+"/stats/clients/heartbeats"
+	defer func() { clientsServiceURL, heartbeatServiceURL = cu, hu }()
+
+	clientMu.Lock()
+	registered = false
+	clientMu.Unlock()
+
+	// sim bootstrap peers and parsed log
+	setPeers(16, 16)
+	setAddr("0x8d25fa2e7d")
+	NewP2PNumPeers().Parse(P2PNumPeersEx)
+
+	// test first heartbeat registers client
+	if err := sendHeartbeat(3); err != nil {
+		t.Fatalf("data.sendHeartbeat() returned error: %v", err)
+	}
+
+	// test next heartbeat carries log time and queue depth
+	if err := sendHeartbeat(3); err != nil {
+		t.Fatalf("data.sendHeartbeat() returned error: %v", err)
+	}
+
+	if len(paths) != 2 || paths[0] != "/stats/clients" || paths[1] != "/stats/clients/heartbeats" {
+		t.Fatalf("data.sendHeartbeat() sent: %v", paths)
+	}
+
+	if hb.QueueDepth != 3 || hb.ClientID != clientID || !hb.LastLogAt.Equal(LastLogTime()) || hb.LastLogAt.IsZero() {
+		t.Fatalf("data.sendHeartbeat() sent: %+v", hb)
+	}
+}
+
+func TestSendHeartbeatAddrKnown(t *testing.T) {
+	// setup test variables
+	var regs []Registration
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stats/clients" {
+			var reg Registration
+			b, _ := io.ReadAll(r.Body)
+			json.Unmarshal(b, &reg)
+			regs = append(regs, reg)
+		}
+	}))
+	defer srv.Close()
+
+	cu, hu := clientsServiceURL, heartbeatServiceURL
+	clientsServiceURL, heartbeatServiceURL = srv.URL+"/stats/clients", srv.URL+"/stats/clients/heartbeats"
+	defer func() { clientsServiceURL, heartbeatServiceURL = cu, hu }()
+
+	// sim registration before bootstrap found the address
+	setAddr("")
+	defer setAddr("0x8d25fa2e7d")
+	if err := Register(); err != nil {
+		t.Fatalf("data.Register() returned error: %v", err)
+	}
+
+	// test heartbeat without address keeps registration
+	sendHeartbeat(0)
+	if len(regs) != 1 {
+		t.Fatalf("data.sendHeartbeat() registered: %v times, wanted: %v", len(regs), 1)
+	}
+
+	// test address learned later registers again, then only heartbeats
+	setAddr("0x8d25fa2e7d")
+	sendHeartbeat(0)
+	sendHeartbeat(0)
+	if len(regs) != 2 || regs[1].Addr != "0x8d25fa2e7d" {
+		t.Fatalf("data.sendHeartbeat() registered: %+v, wanted address: %v", regs, "0x8d25fa2e7d")
+	}
+}
+
+func TestCheckServer(t *testing.T) {
+	// setup test variables
+	var want = http.StatusUnauthorized
+
+	var method, path string
+	var key string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, key = r.Method, r.URL.Path, r.Header.Get("X-Api-Key")
+		w.WriteHeader(want)
+	}))
+	defer srv.Close()
+
+	au := authServiceURL
+	authServiceURL = srv.URL + "/stats/clients/auth"
+	defer func() { authServiceURL = au }()
+
+	// test rejected key status returned
+	got, err := CheckServer()
+	if err != nil || got != want {
+		t.Fatalf("data.CheckServer() returned: %v, %v, wanted: %v", got, err, want)
+	}
+
+	// test authenticated auth check, no heartbeat recorded
+	if method != http.MethodGet || path != "/stats/clients/auth" || key == "" {
+		t.Fatalf("data.CheckServer() sent: %v %v, key: %q, wanted: %v %v", method, path, key, http.MethodGet, "/stats/clients/auth")
+	}
+
+	// test unreachable server error
+	srv.Close()
+	if got, err = CheckServer(); err == nil {
+		t.Fatalf("data.CheckServer() returned: %v, wanted error", got)
+	}
+}
+
+func TestMarkLogTime(t *testing.T) {
+	// test only newer log times kept
+	now := time.Now().Add(time.Hour)
+	markLogTime(now)
+	markLogTime(now.Add(-time.Minute))
+
+	if got := LastLogTime(); !got.Equal(now) {
+		t.Fatalf("data.LastLogTime() returned: %v, wanted: %v", got, now)
+	}
+}
+
+func TestHeartbeatIntervalFromEnv(t *testing.T) {
+	defer os.Unsetenv("HEARTBEAT_INTERVAL")
+
+	// test default
+	if got, _ := HeartbeatIntervalFromEnv(); got != defaultHeartbeatInterval {
+		t.Fatalf("data.HeartbeatIntervalFromEnv() returned: %v, wanted: %v", got, defaultHeartbeatInterval)
+	}
+
+	// test invalid interval
+	os.Setenv("HEARTBEAT_INTERVAL", "0s")
+	if got, err := HeartbeatIntervalFromEnv(); err == nil {
+		t.Fatalf("data.HeartbeatIntervalFromEnv() returned: %v, wanted error", got)
+	}
+}
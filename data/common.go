@@ -1,22 +1,22 @@
 package data
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"math/rand"
 	"net/http"
-	"regexp"
-	"strings"
 	"time"
 )
 
-const (
-	isoDatetimeRE = `(?m)\d+-\d+-\d+\s?\w?\d+:\d+:\d+\.?\d*?\w+`
-	timeStrFormat = `2006-01-02T15:04:05.999`
+var (
+	apiAddr = "http://127.0.0.1:8000"
+	apiKey  = devAPIKey
 )
 
 var (
-	apiAddr = "http://127.0.0.1:8000"
-	apiKey  = "devkey"
+	errAtoi    = errors.New("invalid integer")
+	errNoMatch = errors.New("no match")
 )
 
 type Parser interface {
@@ -24,62 +24,246 @@ type Parser interface {
 	Parse([]byte) error
 }
 
+type Job struct {
+	URL  string
+	Body []byte
+	Key  string // dedup key, empty if record has no id
+}
+
 func SendData(p Parser, b []byte) error {
-	// parse log
+	// parse log, keep lines failing on format
 	if err := p.Parse(b); err != nil {
+		QuarantineLine(p, b, err)
 		return err
 	}
 
-	// create json
-	d, err := p.ToJSON()
+	j, err := Encode(p)
 	if err != nil {
 		return err
 	}
 
-	// fuzzing request
-	fuzzRequest()
+	// hand off to scheduler, else send inline
+	if scheduler != nil {
+		if err := scheduler.Submit(j); err != nil {
+			dedup.release(j.Key)
+			return err
+		}
+		return nil
+	}
 
-	// network request
-	url := getServiceURI(p)
-	j := strings.NewReader(string(d))
-	req, err := http.NewRequest(http.MethodPost, url, j)
+	return deliver(j)
+}
+
+func Deliver(p Parser) error {
+	// send parsed record now, bypassing scheduler, ie error once server rejected it
+	// old lines, ie retried, leave the height watermark as is
+	j, err := encode(p, false)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Add("X-Api-Key", apiKey)
-	req.Header.Set("Content-Type", "application/json")
+	return deliver(j)
+}
 
-	// get request status code
-	resp, err := http.DefaultClient.Do(req)
+func Encode(p Parser) (Job, error) {
+	return encode(p, true)
+}
+
+func encode(p Parser, watermark bool) (Job, error) {
+	// rate limit on send, ie parsing alone never counts a line
+	if s, ok := p.(Sampler); ok && !s.Sample() {
+		return Job{}, errThrottled
+	}
+
+	// skip records already sent or queued
+	var key string
+	if i, ok := p.(Identifier); ok {
+		key = i.ID()
+		if !dedup.reserve(key) {
+			return Job{}, errDuplicate
+		}
+	}
+
+	// tag or quarantine suspicious records, new records only
+	if err := validate(p); err != nil {
+		if !IsQuarantined(err) {
+			dedup.release(key)
+			return Job{}, err
+		}
+		// quarantined once, ie not written again when seen again
+		if key != "" {
+			if cerr := dedup.commit(key); cerr != nil {
+				return Job{}, cerr
+			}
+		}
+		return Job{}, err
+	}
+
+	// create json
+	d, err := p.ToJSON()
+	if err != nil {
+		dedup.release(key)
+		return Job{}, err
+	}
+	if watermark {
+		acceptHeight(p)
+	}
+
+	return Job{URL: getServiceURI(p), Body: d, Key: key}, nil
+}
+
+func Preview(p Parser) (Job, error) {
+	// job as it would be sent, without reserving the record
+	if v, ok := p.(Validator); ok {
+		v.Validate()
+	}
+
+	d, err := p.ToJSON()
+	if err != nil {
+		return Job{}, err
+	}
+
+	var key string
+	if i, ok := p.(Identifier); ok {
+		key = i.ID()
+	}
+
+	return Job{URL: getServiceURI(p), Body: d, Key: key}, nil
+}
+
+func Dispatch(ctx context.Context, j Job) error {
+	// wait for queue space, else send inline
+	if scheduler != nil {
+		if err := scheduler.SubmitWait(ctx, j); err != nil {
+			dedup.release(j.Key)
+			return err
+		}
+		return nil
+	}
+
+	return deliver(j)
+}
+
+func IsDuplicate(err error) bool {
+	return err == errDuplicate
+}
+
+func IsNoMatch(err error) bool {
+	return err == errNoMatch
+}
+
+func IsThrottled(err error) bool {
+	return err == errThrottled
+}
+
+func ServerAddr() string {
+	return apiAddr
+}
+
+func deliver(j Job) error {
+	// remember records accepted or already known by server
+	err := postData(j)
+	if j.Key != "" {
+		if err != nil {
+			dedup.release(j.Key)
+		} else if err := dedup.commit(j.Key); err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+func postData(j Job) error {
+	on, _ := compressing()
+	resp, gz, err := doPost(j, on)
 	if err != nil {
 		return err
 	}
+
+	// server rejected gzip, stop compressing and resend plain
+	if gz && resp.StatusCode == http.StatusUnsupportedMediaType {
+		resp.Body.Close()
+		disableCompression()
+		resp, _, err = doPost(j, false)
+		if err != nil {
+			return err
+		}
+	}
 	defer resp.Body.Close()
 	fmt.Printf("~%s %s %v\n", resp.Request.URL.Path, resp.Request.Method, resp.StatusCode)
 
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusConflict {
+		s := fmt.Sprintf("unexpected status: %d", resp.StatusCode)
+		return errors.New(s)
+	}
+
 	return nil
 }
 
-func parseTime(b []byte) (time.Time, error) {
-	re := regexp.MustCompile(isoDatetimeRE)
-	re.Longest()
+func doPost(j Job, compress bool) (*http.Response, bool, error) {
+	return doRequest(http.MethodPost, j, compress)
+}
+
+func doRequest(method string, j Job, compress bool) (*http.Response, bool, error) {
+	body := j.Body
+	var gz bool
+	if compress {
+		var err error
+		body, gz, err = compressBody(j.Body)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	// network request
+	r := bytes.NewReader(body)
+	req, err := http.NewRequest(method, j.URL, r)
+	if err != nil {
+		return nil, false, err
+	}
+
+	key, err := credentials.APIKey()
+	if err != nil {
+		return nil, false, err
+	}
+
+	// sign body as sent with api key, else send key as is
+	if signRequests {
+		if err := signRequest(req, body, key, time.Now()); err != nil {
+			return nil, false, err
+		}
+	} else {
+		req.Header.Add("X-Api-Key", key)
+	}
+	if len(body) > 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if gz {
+		req.Header.Set("Content-Encoding", CompressGzip)
+	}
+	if j.Key != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey(j.Key))
+	}
 
-	m := re.Find(b)
-	s := strings.Replace(string(m), " ", "T", -1)
+	// get request status code
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
 
-	// get relevant locations
-	loc := time.Now().Location()       // loc, err := time.LoadLocation("Local")
-	utc := time.Now().UTC().Location() // utc, err := time.LoadLocation("UTC")
+	return resp, gz, nil
+}
 
-	// parse time at location
-	t, err := time.ParseInLocation(timeStrFormat, s, loc)
+func parseTime(b []byte) (time.Time, error) {
+	lt, err := parseTimes(b)
 	if err != nil {
-		return t, err
+		return time.Time{}, err
 	}
 
-	// return time at utc
-	return t.In(utc), nil
+	// return time from configured source at utc
+	t, _ := lt.createdAt()
+	return t, nil
 }
 
 func getServiceURI(p Parser) string {
@@ -90,14 +274,84 @@ func getServiceURI(p Parser) string {
 		url = umBroadcastedServiceURL
 	case *P2PNumPeers:
 		url = p2pNumPeersServiceURL
+	case *NSProgress:
+		url = nsProgressServiceURL
+	case *CSProgress:
+		url = csProgressServiceURL
+	case *Incident:
+		url = incidentServiceURL
 	}
 
 	return url
 }
 
-func fuzzRequest() {
-	// rethink how seed is randomized
-	rand.Seed(time.Now().UnixNano())
-	d := time.Duration((rand.Intn(6000)))
-	time.Sleep(d * time.Millisecond)
+func nextKeyValue(b []byte) (key, value, rest []byte) {
+	// scan for the next "key: value" pair, ie `(\w+):\s+(\w+),?`
+	for i := 0; i < len(b); i++ {
+		if b[i] != ':' {
+			continue
+		}
+
+		// key is the word run ending at the colon
+		k := i
+		for k > 0 && isWordChar(b[k-1]) {
+			k--
+		}
+		if k == i {
+			continue
+		}
+
+		// colon must be followed by at least one space
+		v := i + 1
+		for v < len(b) && isSpaceChar(b[v]) {
+			v++
+		}
+		if v == i+1 {
+			continue
+		}
+
+		// value is the word run after the spaces
+		e := v
+		for e < len(b) && isWordChar(b[e]) {
+			e++
+		}
+		if e == v {
+			continue
+		}
+
+		// consume optional trailing comma
+		r := e
+		if r < len(b) && b[r] == ',' {
+			r++
+		}
+
+		return b[k:i], b[v:e], b[r:]
+	}
+
+	return nil, nil, nil
+}
+
+func atoi(b []byte) (int, error) {
+	// parse decimal integer without allocating a string
+	if len(b) == 0 || len(b) > 18 {
+		return 0, errAtoi
+	}
+
+	var n int
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, errAtoi
+		}
+		n = n*10 + int(c-'0')
+	}
+
+	return n, nil
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isSpaceChar(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
 }
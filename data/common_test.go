@@ -1,6 +1,11 @@
 package data
 
 import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 )
@@ -11,6 +16,11 @@ var (
 	ErrLogEx   = []byte("[2021-08-28  09:10:32] [info] [ThetaEdgeLauncher] [2021-08-28  09:10:32] ...")
 )
 
+var (
+	NSLogEx    = []byte("[2021-08-28 09:10:33.102] [info] [ThetaEdgeLauncher] [2021-08-28 09:10:33]  INFO [netsync] Received block, height: 11759202")
+	OtherLogEx = []byte("[2021-08-28 09:10:33.415] [info] [ThetaEdgeLauncher] [2021-08-28 09:10:33]  INFO [consensus] Handling proposal ...")
+)
+
 func TestParseTime(t *testing.T) {
 	// setup test vars
 	var log []byte
@@ -20,9 +30,9 @@ func TestParseTime(t *testing.T) {
 	var loc = time.Now().Location()
 	var utc = time.Now().UTC().Location()
 
-	// test uptime miner log
+	// test uptime miner log, ie node timestamp drives created time
 	log = UMBroadcastedEx
-	want, _ = time.ParseInLocation("2006-01-02T15:04:05.999", "2021-08-28T09:00:26.951", loc)
+	want, _ = time.ParseInLocation("2006-01-02T15:04:05.999", "2021-08-28T09:00:26", loc)
 	want = want.In(utc)
 
 	got, err = parseTime(log)
@@ -36,7 +46,7 @@ func TestParseTime(t *testing.T) {
 
 	// test p2p log
 	log = P2PLogEx01
-	want, _ = time.ParseInLocation("2006-01-02T15:04:05.999", "2021-08-28T09:10:32.888", loc)
+	want, _ = time.ParseInLocation("2006-01-02T15:04:05.999", "2021-08-28T09:10:32", loc)
 	want = want.In(utc)
 
 	got, err = parseTime(log)
@@ -62,8 +72,22 @@ func TestParseTime(t *testing.T) {
 		t.Fatalf("data.parseTime() returned: %v, wanted: %v", got, want)
 	}
 
-	// test time string error
+	// test double space between date and time
 	log = ErrLogEx
+	want, _ = time.ParseInLocation("2006-01-02T15:04:05", "2021-08-28T09:10:32", loc)
+	want = want.In(utc)
+
+	got, err = parseTime(log)
+	if err != nil {
+		t.Fatalf("data.parseTime() returned: %v", err)
+	}
+
+	if got != want {
+		t.Fatalf("data.parseTime() returned: %v, wanted: %v", got, want)
+	}
+
+	// test time string error
+	log = []byte("[info] [ThetaEdgeLauncher] [2021-08-28 0910:32] ...")
 
 	got, err = parseTime(log)
 	if err == nil {
@@ -92,9 +116,233 @@ func TestGetServiceURI(t *testing.T) {
 	if got != want {
 		t.Fatalf("data.getServiceURI() returned: %v, wanted: %v", got, want)
 	}
+
+	// test netsync progress service uri
+	p = NewNSProgress()
+	want = nsProgressServiceURL
+	got = getServiceURI(p)
+	if got != want {
+		t.Fatalf("data.getServiceURI() returned: %v, wanted: %v", got, want)
+	}
+
+	// test consensus progress service uri
+	p = NewCSProgress()
+	want = csProgressServiceURL
+	got = getServiceURI(p)
+	if got != want {
+		t.Fatalf("data.getServiceURI() returned: %v, wanted: %v", got, want)
+	}
+
+	// test incident service uri
+	p = NewIncident()
+	want = incidentServiceURL
+	got = getServiceURI(p)
+	if got != want {
+		t.Fatalf("data.getServiceURI() returned: %v, wanted: %v", got, want)
+	}
+}
+
+func TestPreview(t *testing.T) {
+	// sim parsed record
+	p := &P2PNumPeers{Addr: "0x8d25fa2e7d", NumPeers: 16, SufficientPeers: 16}
+
+	// test job built without reserving record
+	got, err := Preview(p)
+	if err != nil {
+		t.Fatalf("data.Preview() returned error: %v", err)
+	}
+
+	if got.URL != p2pNumPeersServiceURL || got.Key != p.ID() || len(got.Body) == 0 {
+		t.Fatalf("data.Preview() returned: %+v", got)
+	}
+
+	if !dedup.reserve(got.Key) {
+		t.Fatalf("data.Preview() reserved record: %v", got.Key)
+	}
+	dedup.release(got.Key)
 }
 
-func TestFuzzRequest(t *testing.T) {
-	// rethink how to test this
-	fuzzRequest()
+func TestNextKeyValue(t *testing.T) {
+	// setup test vars
+	var log []byte
+	var got [][2]string
+	var want [][2]string
+
+	// test uptime broadcast log
+	log = UMBroadcastedEx
+	want = [][2]string{
+		{"vote", "EENVote"},
+		{"Block", "0x6d0ae6972cd670a8f7dfd628ef516051d0fd699906c55f80cff12540bd3786a8"},
+		{"Height", "11759001"},
+		{"Address", "0x8d25fa2e7d"},
+		{"Signature", "E1A06D0AE697786A8"},
+		{"CreationTimestamp", "1630155386"},
+	}
+
+	got = nil
+	for k, v, rest := nextKeyValue(log); k != nil; k, v, rest = nextKeyValue(rest) {
+		got = append(got, [2]string{string(k), string(v)})
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("data.nextKeyValue() returned: %v, wanted: %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("data.nextKeyValue() returned: %v, wanted: %v", got, want)
+		}
+	}
+
+	// test no pairs, ie timestamps only
+	log = []byte("[2021-08-28 09:10:32.888] [info] key:value key :value")
+	if k, v, _ := nextKeyValue(log); k != nil {
+		t.Fatalf("data.nextKeyValue() returned: %s, %s, wanted: nil", k, v)
+	}
+}
+
+func TestAtoi(t *testing.T) {
+	// test valid integer
+	got, err := atoi([]byte("11759001"))
+	if err != nil {
+		t.Fatalf("data.atoi() returned error: %v", err)
+	}
+
+	if got != 11759001 {
+		t.Fatalf("data.atoi() returned: %v, wanted: %v", got, 11759001)
+	}
+
+	// test invalid integers
+	for _, b := range [][]byte{nil, []byte("0x1f"), []byte("-1"), []byte("1234567890123456789")} {
+		if got, err := atoi(b); err == nil {
+			t.Fatalf("data.atoi(%q) returned: %v, wanted error", b, got)
+		}
+	}
+}
+
+// genLogFixture builds a log of at least size bytes resembling a running
+// edge node: mostly unmatched noise, peers, sync and votes, occasional long
+// warn/error lines, and rotated content, ie older lines after a restart and
+// a line cut off mid-write.
+func genLogFixture(size int) []byte {
+	r := rand.New(rand.NewSource(1))
+	t := time.Date(2021, 8, 28, 9, 0, 0, 0, time.UTC)
+	height := 11759001
+	round := 1
+
+	noise := []string{
+		"INFO [consensus] Handling proposal, proposal: {Block: 0x6d0ae6972cd670a8f7dfd628ef516051d0fd699906c55f80cff12540bd3786a8}",
+		"INFO [consensus] Vote received, vote: {Block: 0x6d0ae6972cd670a8f7dfd628ef516051d0fd699906c55f80cff12540bd3786a8, ID: 0x2e833968e5}",
+		"INFO [netsync] Peer disconnected, peer: 0x9ab1c2d3e4",
+		"INFO [rpc] Handling request, method: theta.GetStatus",
+		"INFO [blockchain] Block finalized, height: %d",
+		"DEBU [p2p] Received message, peer: 0x2e833968e5, type: 3",
+	}
+
+	line := func(level, msg string) string {
+		// launcher millis then node seconds, as edge node writes them
+		return fmt.Sprintf("[%s] [%s] [ThetaEdgeLauncher] [%s]  %s",
+			t.Format("2006-01-02 15:04:05.000"), level, t.Format("2006-01-02 15:04:05"), msg)
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(size + 4096)
+	for buf.Len() < size {
+		t = t.Add(time.Duration(r.Intn(400)) * time.Millisecond)
+
+		var s string
+		switch n := r.Intn(100); true {
+		case n < 55:
+			msg := noise[r.Intn(len(noise))]
+			if strings.Contains(msg, "%d") {
+				msg = fmt.Sprintf(msg, height)
+			}
+			s = line("info", msg)
+		case n < 70:
+			s = line("info", fmt.Sprintf("INFO [netsync] Received block, height: %d", height))
+		case n < 80:
+			s = line("info", fmt.Sprintf("INFO [p2p] Already has sufficient number of peers, numPeers: %d, sufficientNumPeers: 16", 10+r.Intn(12)))
+		case n < 84:
+			height++
+			s = line("info", fmt.Sprintf("INFO [uptime miner] Received block: 0x%064x, height: %d, epoch: %d", r.Int63(), height, height+82047))
+		case n < 88:
+			round++
+			s = line("info", fmt.Sprintf("INFO [uptime miner] Start new round: %d", round))
+		case n < 93:
+			s = line("info", fmt.Sprintf("INFO [uptime miner] Broadcasted vote: EENVote{Block: 0x%064x, Height: %d, Address: 0x8d25fa2e7d, Signature: %X, CreationTimestamp: %d}", r.Int63(), height, r.Int63(), t.Unix()))
+		case n < 97:
+			// long incident lines, ie wrapped errors and stack dumps
+			s = line("info", "WARN [p2p] Failed to connect to peer, peer: 0x2e833968e5, error: "+strings.Repeat("dial tcp 10.0.0.1:30001: i/o timeout; ", 10+r.Intn(50)))
+		case n < 99:
+			s = fmt.Sprintf("[%s] [error] Edge node process exited, code: %d", t.Format("2006-01-02 15:04:05.000"), 1+r.Intn(2))
+		default:
+			// rotated content, older lines and a line cut mid-write
+			old := t.Add(-time.Duration(1+r.Intn(60)) * time.Minute)
+			prev := t
+			t = old
+			s = line("info", fmt.Sprintf("INFO [netsync] Received block, height: %d", height-100))
+			s = s[:len(s)/2] + "\n" + line("info", "INFO [rpc] Handling request, method: theta.GetVersion")
+			t = prev
+		}
+
+		buf.WriteString(s)
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes()
+}
+
+func BenchmarkNextKeyValue(b *testing.B) {
+	log := UMBroadcastedEx
+	b.ReportAllocs()
+	b.SetBytes(int64(len(log)))
+
+	for i := 0; i < b.N; i++ {
+		for k, _, rest := nextKeyValue(log); k != nil; k, _, rest = nextKeyValue(rest) {
+		}
+	}
+}
+
+func BenchmarkParseTime(b *testing.B) {
+	log := UMBroadcastedEx
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := parseTime(log); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkScanLog(b *testing.B) {
+	// sim bootstrap address and peers
+	setAddr("0x8d25fa2e7d")
+	setPeers(16, 16)
+
+	fixture := genLogFixture(4 << 20)
+	lines := bytes.Count(fixture, []byte("\n"))
+
+	var before, after runtime.MemStats
+	b.ReportAllocs()
+	b.SetBytes(int64(len(fixture)))
+	b.ResetTimer()
+	runtime.ReadMemStats(&before)
+
+	for i := 0; i < b.N; i++ {
+		rest := fixture
+		for len(rest) > 0 {
+			n := bytes.IndexByte(rest, '\n')
+			line := rest[:n]
+			rest = rest[n+1:]
+
+			if p := NewParser(Filter(line)); p != nil {
+				_ = p.Parse(line)
+			}
+		}
+	}
+
+	runtime.ReadMemStats(&after)
+	b.StopTimer()
+
+	b.ReportMetric(float64(lines), "lines/op")
+	b.ReportMetric(float64(after.Mallocs-before.Mallocs)/float64(b.N*lines), "allocs/line")
 }
@@ -0,0 +1,131 @@
+package data
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	CompressNone = "none"
+	CompressGzip = "gzip"
+	CompressAuto = "auto" // probe server for gzip support
+
+	defaultCompressMinSize = 256 // ie a vote record, smaller ones gain little
+)
+
+var (
+	compressMu      sync.RWMutex // guards compression state below, sends run concurrently
+	compressBodies  bool
+	compressMinSize = defaultCompressMinSize
+)
+
+func SetCompression(mode string, min int) error {
+	var on bool
+
+	switch mode {
+	case "", CompressNone:
+	case CompressGzip:
+		on = true
+	case CompressAuto:
+		ok, err := ProbeCompression(umBroadcastedServiceURL)
+		if err != nil {
+			return err
+		}
+		on = ok
+	default:
+		s := fmt.Sprintf("unknown compression: %s", mode)
+		return errors.New(s)
+	}
+
+	compressMu.Lock()
+	defer compressMu.Unlock()
+
+	compressBodies = on
+	compressMinSize = min
+
+	return nil
+}
+
+func compressing() (bool, int) {
+	compressMu.RLock()
+	defer compressMu.RUnlock()
+
+	return compressBodies, compressMinSize
+}
+
+func disableCompression() {
+	// server rejected gzip, ie 415
+	compressMu.Lock()
+	defer compressMu.Unlock()
+
+	compressBodies = false
+}
+
+func CompressionFromEnv() error {
+	min := defaultCompressMinSize
+	if v := os.Getenv("COMPRESSION_MIN_BYTES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			s := fmt.Sprintf("invalid COMPRESSION_MIN_BYTES: %s", v)
+			return errors.New(s)
+		}
+		min = n
+	}
+
+	return SetCompression(os.Getenv("COMPRESSION"), min)
+}
+
+func ProbeCompression(url string) (bool, error) {
+	// servers advertise accepted request encodings, ie rfc 7694
+	req, err := http.NewRequest(http.MethodOptions, url, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	for _, v := range resp.Header.Values("Accept-Encoding") {
+		for _, e := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(strings.SplitN(e, ";", 2)[0]), CompressGzip) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func compressBody(b []byte) ([]byte, bool, error) {
+	// small bodies cost more to compress than to send
+	on, min := compressing()
+	if !on || len(b) < min {
+		return b, false, nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(b); err != nil {
+		return b, false, err
+	}
+	if err := zw.Close(); err != nil {
+		return b, false, err
+	}
+
+	// keep plain body when gzip does not shrink it
+	if buf.Len() >= len(b) {
+		return b, false, nil
+	}
+
+	return buf.Bytes(), true, nil
+}
@@ -0,0 +1,203 @@
+package data
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestCompressBody(t *testing.T) {
+	defer SetCompression(CompressNone, defaultCompressMinSize)
+
+	// setup test variables
+	var small = []byte(`{"height":11759001}`)
+	var large = bytes.Repeat(small, 100)
+
+	// test disabled
+	SetCompression(CompressNone, 0)
+	if got, gz, _ := compressBody(large); gz || !bytes.Equal(got, large) {
+		t.Fatalf("data.compressBody() returned compressed: %v, wanted: %v", gz, false)
+	}
+
+	// test below threshold left plain
+	SetCompression(CompressGzip, 1024)
+	if got, gz, _ := compressBody(small); gz || !bytes.Equal(got, small) {
+		t.Fatalf("data.compressBody() returned compressed: %v, wanted: %v", gz, false)
+	}
+
+	// test above threshold round trips
+	got, gz, err := compressBody(large)
+	if err != nil || !gz || len(got) >= len(large) {
+		t.Fatalf("data.compressBody() returned: %v bytes, %v, %v", len(got), gz, err)
+	}
+
+	zr, _ := gzip.NewReader(bytes.NewReader(got))
+	if b, _ := io.ReadAll(zr); !bytes.Equal(b, large) {
+		t.Fatalf("data.compressBody() did not round trip")
+	}
+
+	// test body gzip would grow left plain
+	SetCompression(CompressGzip, 0)
+	if got, gz, _ := compressBody(small); gz || !bytes.Equal(got, small) {
+		t.Fatalf("data.compressBody() returned compressed: %v, wanted: %v", gz, false)
+	}
+
+	// test unknown mode
+	if err := SetCompression("brotli", 0); err == nil {
+		t.Fatalf("data.SetCompression() returned: %v, wanted error", err)
+	}
+}
+
+func TestProbeCompression(t *testing.T) {
+	// setup test variables
+	var accept string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if accept != "" {
+			w.Header().Set("Accept-Encoding", accept)
+		}
+	}))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"identity", false},
+		{"br, GZIP;q=0.8", true},
+	} {
+		accept = tc.accept
+		got, err := ProbeCompression(srv.URL)
+		if err != nil {
+			t.Fatalf("data.ProbeCompression() returned error: %v", err)
+		}
+
+		if got != tc.want {
+			t.Fatalf("data.ProbeCompression(%q) returned: %v, wanted: %v", tc.accept, got, tc.want)
+		}
+	}
+}
+
+func TestPostDataCompressed(t *testing.T) {
+	defer SetCompression(CompressNone, defaultCompressMinSize)
+
+	// setup test variables
+	var body = bytes.Repeat([]byte(`{"height":11759001}`), 100)
+	var encodings []string
+	var reject bool
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enc := r.Header.Get("Content-Encoding")
+		encodings = append(encodings, enc)
+		if enc == CompressGzip && reject {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+
+		var rd io.Reader = r.Body
+		if enc == CompressGzip {
+			rd, _ = gzip.NewReader(r.Body)
+		}
+		if b, _ := io.ReadAll(rd); !bytes.Equal(b, body) {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	// test gzip body accepted
+	SetCompression(CompressGzip, 0)
+	if err := postData(Job{URL: srv.URL, Body: body}); err != nil {
+		t.Fatalf("data.postData() returned error: %v", err)
+	}
+
+	if len(encodings) != 1 || encodings[0] != CompressGzip {
+		t.Fatalf("data.postData() sent encodings: %v, wanted: %v", encodings, []string{CompressGzip})
+	}
+
+	// test fall back to plain once server rejects gzip
+	encodings, reject = nil, true
+	if err := postData(Job{URL: srv.URL, Body: body}); err != nil {
+		t.Fatalf("data.postData() returned error: %v", err)
+	}
+
+	if on, _ := compressing(); len(encodings) != 2 || encodings[1] != "" || on {
+		t.Fatalf("data.postData() sent encodings: %v, wanted: %v", encodings, []string{CompressGzip, ""})
+	}
+}
+
+func TestDoPostRecordCompressed(t *testing.T) {
+	defer SetCompression(CompressNone, defaultCompressMinSize)
+
+	// setup test variables
+	var got []byte
+	var enc string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enc = r.Header.Get("Content-Encoding")
+		var rd io.Reader = r.Body
+		if enc == CompressGzip {
+			rd, _ = gzip.NewReader(r.Body)
+		}
+		got, _ = io.ReadAll(rd)
+	}))
+	defer srv.Close()
+
+	setPeers(16, 16)
+	um := NewUMBroadcast()
+	if err := um.Parse(UMBroadcastedEx); err != nil {
+		t.Fatalf("data.UMBroadcast.Parse() returned error: %v", err)
+	}
+	body, err := um.ToJSON()
+	if err != nil {
+		t.Fatalf("data.UMBroadcast.ToJSON() returned error: %v", err)
+	}
+
+	// test single vote record compressed with default threshold
+	SetCompression(CompressGzip, defaultCompressMinSize)
+	resp, gz, err := doPost(Job{URL: srv.URL, Body: body}, true)
+	if err != nil {
+		t.Fatalf("data.doPost() returned error: %v", err)
+	}
+	resp.Body.Close()
+
+	if !gz || enc != CompressGzip || !bytes.Equal(got, body) {
+		t.Fatalf("data.doPost() sent: %v, %v, wanted: %v, %s", enc, string(got), CompressGzip, body)
+	}
+}
+
+func TestPostDataCompressedConcurrent(t *testing.T) {
+	// setup test variables
+	var body = bytes.Repeat([]byte(`{"height":11759001}`), 100)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") == CompressGzip {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+		}
+	}))
+	defer srv.Close()
+
+	defer SetCompression(CompressNone, defaultCompressMinSize)
+	SetCompression(CompressGzip, 0)
+
+	// test concurrent sends fall back to plain, run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := postData(Job{URL: srv.URL, Body: body}); err != nil {
+				t.Errorf("data.postData() returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if on, _ := compressing(); on {
+		t.Fatalf("data.compressing() returned: %v, wanted: %v", on, false)
+	}
+}
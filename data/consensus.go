@@ -0,0 +1,111 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	csSampleEvery = 30 * time.Second
+)
+
+var (
+	csProgressServiceURL = fmt.Sprintf("%s/stats/uptimes/epochs", apiAddr)
+	keyEpoch             = []byte("epoch")
+	keyRound             = []byte("round")
+)
+
+type CSProgress struct {
+	Addr         string     `json:"address"`
+	Epoch        int        `json:"epoch"`
+	Height       int        `json:"height"`
+	Round        int        `json:"round"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LauncherTime *time.Time `json:"launcher_time,omitempty"`
+	NodeTime     *time.Time `json:"node_time,omitempty"`
+	TimeSource   string     `json:"time_source"`
+}
+
+func NewCSProgress() *CSProgress {
+	return &CSProgress{}
+}
+
+func (cs *CSProgress) ToJSON() ([]byte, error) {
+	return json.Marshal(cs)
+}
+
+func (cs *CSProgress) ID() string {
+	return fmt.Sprintf("epoch:%s:%d:%d", cs.Addr, cs.Epoch, cs.CreatedAt.UnixNano())
+}
+
+func (cs *CSProgress) Parse(b []byte) error {
+	if !bytes.Contains(b, consensus) {
+		return errNoMatch
+	}
+
+	var ve int
+	var vh int
+	var vr int
+
+	// unknown keys ignored, only epoch progress is of interest
+	for k, v, rest := nextKeyValue(b); k != nil; k, v, rest = nextKeyValue(rest) {
+		var dst *int
+		switch true {
+		case bytes.EqualFold(k, keyEpoch):
+			dst = &ve
+		case bytes.EqualFold(k, keyHeight):
+			dst = &vh
+		case bytes.EqualFold(k, keyRound):
+			dst = &vr
+		default:
+			continue
+		}
+
+		n, err := atoi(v)
+		if err != nil {
+			return err
+		}
+		*dst = n
+	}
+
+	// not a progress line, ie handling proposal or vote received
+	if ve == 0 {
+		return errNoMatch
+	}
+
+	lt, err := parseTimes(b)
+	if err != nil {
+		return err
+	}
+	t, ts := lt.createdAt()
+
+	// return error if node not yet bootstrapped with address
+	addr := getAddr()
+	if addr == "" {
+		return errNoAddr
+	}
+
+	cs.Addr = addr
+	cs.Epoch = ve
+	cs.Height = vh
+	cs.Round = vr
+	cs.CreatedAt = t
+	cs.LauncherTime = optTime(lt.launcher)
+	cs.NodeTime = optTime(lt.node)
+	cs.TimeSource = ts
+	markLogTime(t)
+
+	return nil
+}
+
+func (cs *CSProgress) Sample() bool {
+	// consensus logs every block, tracked with received blocks, ie one epoch tracker
+	s, ok := progress.sampleBlock(cs.Addr, cs.CreatedAt, cs.Height, cs.Epoch)
+	if s != nil {
+		PublishEvent(NewEvent(consensusProgressEvent, s))
+	}
+
+	return ok
+}
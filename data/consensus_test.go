@@ -0,0 +1,104 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+)
+
+var (
+	CSEpochEx = []byte("[2021-08-28 09:17:11.831] [info] [ThetaEdgeLauncher] [2021-08-28 09:17:11]  INFO [consensus] Entering new epoch, epoch: 11841048, height: 11759201, round: 2")
+)
+
+func TestNewCSProgress(t *testing.T) {
+	want := &CSProgress{}
+	got := NewCSProgress()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("data.NewCSProgress() returned: %v, wanted: %v", got, want)
+	}
+}
+
+func TestCSProgressParse(t *testing.T) {
+	// setup test variables
+	var log []byte
+	var got = NewCSProgress()
+	var want = NewCSProgress()
+	var lt logTimes
+	var err error
+
+	// sim bootstrap address
+	setAddr("0x8d25fa2e7d")
+	defer setAddr("")
+
+	// test parse epoch
+	log = CSEpochEx
+	lt, _ = parseTimes(log)
+	want = &CSProgress{
+		Addr:         "0x8d25fa2e7d",
+		Epoch:        11841048,
+		Height:       11759201,
+		Round:        2,
+		CreatedAt:    lt.node,
+		LauncherTime: optTime(lt.launcher),
+		NodeTime:     optTime(lt.node),
+		TimeSource:   NodeTimeSource,
+	}
+
+	if err = got.Parse(log); err != nil {
+		t.Fatalf("data.CSProgressParse() returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("data.CSProgressParse() returned: %v, wanted: %v", got, want)
+	}
+
+	// test parse not rate limited, ie sampled on send
+	if err = got.Parse(log); err != nil {
+		t.Fatalf("data.CSProgressParse() returned error: %v", err)
+	}
+
+	// test no epoch not a progress line
+	if err = got.Parse([]byte("[2021-08-28 09:17:11]  INFO [consensus] Vote received")); err != errNoMatch {
+		t.Fatalf("data.CSProgressParse() returned: %v, wanted error: %v", err, errNoMatch)
+	}
+
+	// test no match error
+	if err = got.Parse(P2PNumPeersEx); err != errNoMatch {
+		t.Fatalf("data.CSProgressParse() returned: %v, wanted error: %v", err, errNoMatch)
+	}
+}
+
+func TestCSProgressSample(t *testing.T) {
+	// setup test variables
+	var cs = NewCSProgress()
+	var olderLine = []byte("[2021-08-28 09:16:11.831] [info] [ThetaEdgeLauncher] [2021-08-28 09:16:11]  INFO [consensus] Entering new epoch, epoch: 11841040, height: 11759190, round: 1")
+
+	SetConsensusTracker(NewConsensusTracker(defaultProgressInterval, defaultConsensusStall))
+	defer SetConsensusTracker(NewConsensusTracker(defaultProgressInterval, defaultConsensusStall))
+	setAddr("0x8d25fa2e7d")
+	defer setAddr("")
+
+	// test first sample sent and tracked
+	cs.Parse(CSEpochEx)
+	if !cs.Sample() {
+		t.Fatalf("data.CSProgress.Sample() returned: false, wanted: true")
+	}
+	if st := ConsensusStatus().(ConsensusState); st.Epoch != 11841048 || st.Height != 11759201 {
+		t.Fatalf("data.ConsensusStatus() returned: %+v", st)
+	}
+
+	// test sampled within interval
+	if cs.Sample() {
+		t.Fatalf("data.CSProgress.Sample() returned: true, wanted: false")
+	}
+
+	// test older sample neither sent nor tracked
+	older := NewCSProgress()
+	older.Parse(olderLine)
+	if older.Sample() {
+		t.Fatalf("data.CSProgress.Sample() returned: true, wanted: false")
+	}
+	if st := ConsensusStatus().(ConsensusState); st.Epoch != 11841048 {
+		t.Fatalf("data.ConsensusStatus() returned epoch: %v, wanted: %v", st.Epoch, 11841048)
+	}
+}
@@ -0,0 +1,203 @@
+package data
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	defaultDedupSize = 4096
+	dedupFileName    = "sent.log"
+)
+
+var (
+	dedup        = newDedupCache(defaultDedupSize, "")
+	errDuplicate = errors.New("duplicate record")
+)
+
+type Identifier interface {
+	ID() string
+}
+
+type dedupCache struct {
+	mu       sync.Mutex
+	max      int
+	fp       string
+	keys     map[string]struct{}
+	inflight map[string]struct{} // keys queued but not yet sent
+	order    []string            // oldest key first
+	appended int                 // keys appended to file since last compaction
+}
+
+func newDedupCache(max int, fp string) *dedupCache {
+	return &dedupCache{
+		max:      max,
+		fp:       fp,
+		keys:     make(map[string]struct{}, max),
+		inflight: make(map[string]struct{}),
+	}
+}
+
+func SetDedupFile(fp string) error {
+	// default to user cache dir, ie ~/.cache/edgestats/sent.log
+	if fp == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return err
+		}
+		fp = filepath.Join(dir, "edgestats", dedupFileName)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fp), 0700); err != nil {
+		return err
+	}
+
+	c := newDedupCache(defaultDedupSize, fp)
+	if err := c.load(); err != nil {
+		return err
+	}
+	dedup = c
+
+	return nil
+}
+
+func idempotencyKey(id string) string {
+	// hash id so header does not leak record fields
+	h := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(h[:])
+}
+
+func (c *dedupCache) seen(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.keys[key]
+	return ok
+}
+
+func (c *dedupCache) reserve(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// claim key until sent, ie commit, or failed, ie release
+	if _, ok := c.keys[key]; ok {
+		return false
+	}
+	if _, ok := c.inflight[key]; ok {
+		return false
+	}
+	c.inflight[key] = struct{}{}
+
+	return true
+}
+
+func (c *dedupCache) release(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.inflight, key)
+}
+
+func (c *dedupCache) commit(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.inflight, key)
+	return c.add(key)
+}
+
+func (c *dedupCache) add(key string) error {
+	// caller holds lock
+
+	if _, ok := c.keys[key]; ok {
+		return nil
+	}
+	c.insert(key)
+
+	if c.fp == "" {
+		return nil
+	}
+
+	// rewrite file once it holds twice the cache size
+	if c.appended >= 2*c.max {
+		return c.compact()
+	}
+
+	f, err := os.OpenFile(c.fp, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(key + "\n"); err != nil {
+		return err
+	}
+	c.appended++
+
+	return nil
+}
+
+func (c *dedupCache) insert(key string) {
+	// evict oldest key once bound reached
+	if len(c.order) >= c.max {
+		delete(c.keys, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.keys[key] = struct{}{}
+	c.order = append(c.order, key)
+}
+
+func (c *dedupCache) load() error {
+	f, err := os.Open(c.fp)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if key := scanner.Text(); key != "" {
+			if _, ok := c.keys[key]; !ok {
+				c.insert(key)
+			}
+			c.appended++
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (c *dedupCache) compact() error {
+	// write current keys to tmp file and swap in place
+	tmp := c.fp + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	for _, key := range c.order {
+		w.WriteString(key + "\n")
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, c.fp); err != nil {
+		return err
+	}
+	c.appended = len(c.order)
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDedupCache(t *testing.T) {
+	// setup test variables
+	var c = newDedupCache(2, "")
+
+	// test unseen and seen keys
+	if c.seen("a") {
+		t.Fatalf("data.dedupCache.seen() returned: %v, wanted: %v", true, false)
+	}
+
+	c.add("a")
+	if !c.seen("a") {
+		t.Fatalf("data.dedupCache.seen() returned: %v, wanted: %v", false, true)
+	}
+
+	// test oldest key evicted once bound reached
+	c.add("b")
+	c.add("c")
+	if c.seen("a") || !c.seen("b") || !c.seen("c") {
+		t.Fatalf("data.dedupCache returned keys: %v, wanted: %v", c.order, []string{"b", "c"})
+	}
+}
+
+func TestDedupCachePersist(t *testing.T) {
+	// setup test variables
+	var tmp = t.TempDir()
+	var fp = filepath.Join(tmp, dedupFileName)
+	var c = newDedupCache(2, fp)
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		if err := c.add(key); err != nil {
+			t.Fatalf("data.dedupCache.add() returned error: %v", err)
+		}
+	}
+
+	// test file compacted to bounded keys
+	buf, _ := os.ReadFile(fp)
+	if got := strings.Fields(string(buf)); len(got) > 2*c.max {
+		t.Fatalf("data.dedupCache file returned: %v, wanted at most %v keys", got, 2*c.max)
+	}
+
+	// test reload keeps most recent keys
+	r := newDedupCache(2, fp)
+	if err := r.load(); err != nil {
+		t.Fatalf("data.dedupCache.load() returned error: %v", err)
+	}
+
+	if r.seen("a") || r.seen("b") || !r.seen("c") || !r.seen("d") {
+		t.Fatalf("data.dedupCache.load() returned keys: %v, wanted: %v", r.order, []string{"c", "d"})
+	}
+
+	// test missing file
+	r = newDedupCache(2, filepath.Join(tmp, "none.log"))
+	if err := r.load(); err != nil {
+		t.Fatalf("data.dedupCache.load() returned error: %v", err)
+	}
+}
+
+func TestUMBroadcastID(t *testing.T) {
+	// setup test variables
+	var a = NewUMBroadcast()
+	var b = NewUMBroadcast()
+
+	// sim bootstrap peers
+	setPeers(16, 16)
+
+	// test new block and broadcast of same vote share id
+	log := []byte(strings.Replace(string(UMNewBlockEx), "Start new block", "Broadcasted vote", 1))
+	a.Parse(UMNewBlockEx)
+	b.Parse(log)
+
+	if a.ID() != b.ID() {
+		t.Fatalf("data.UMBroadcast.ID() returned: %v, wanted: %v", a.ID(), b.ID())
+	}
+
+	want := "vote:0x8d25fa2e7d:11759201:E1A06D0AE697786A8"
+	if got := a.ID(); got != want {
+		t.Fatalf("data.UMBroadcast.ID() returned: %v, wanted: %v", got, want)
+	}
+}
+
+func TestSendDataDedup(t *testing.T) {
+	// setup test variables
+	var keys []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	url := umBroadcastedServiceURL
+	umBroadcastedServiceURL = fmt.Sprintf("%s/stats/uptimes/broadcasts", srv.URL)
+	defer func() { umBroadcastedServiceURL = url }()
+
+	prev := dedup
+	dedup = newDedupCache(defaultDedupSize, "")
+	defer func() { dedup = prev }()
+
+	// sim bootstrap peers
+	setPeers(16, 16)
+
+	// test first send carries idempotency key
+	if err := SendData(NewUMBroadcast(), UMBroadcastedEx); err != nil {
+		t.Fatalf("data.SendData() returned error: %v", err)
+	}
+
+	want := idempotencyKey("vote:0x8d25fa2e7d:11759001:E1A06D0AE697786A8")
+	if len(keys) != 1 || keys[0] != want {
+		t.Fatalf("data.SendData() sent keys: %v, wanted: %v", keys, []string{want})
+	}
+
+	// test repeat send skipped
+	if err := SendData(NewUMBroadcast(), UMBroadcastedEx); err != errDuplicate {
+		t.Fatalf("data.SendData() returned: %v, wanted: %v", err, errDuplicate)
+	}
+
+	if len(keys) != 1 {
+		t.Fatalf("data.SendData() sent keys: %v, wanted: %v", keys, []string{want})
+	}
+}
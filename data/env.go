@@ -0,0 +1,25 @@
+package data
+
+var (
+	// documented env vars read by the client, see README
+	envVars = []string{
+		"LOG_FILEPATH", "LOG_TIMEZONE", "LOG_TIME_SOURCE", "NODE_ADDRESS", "DEDUP_FILEPATH", "UNPARSED_FILEPATH",
+		"VALIDATION", "VALIDATION_QUARANTINE_FILE", "VALIDATION_MAX_SKEW",
+		"TLS_CA_FILE", "TLS_CERT_FILE", "TLS_KEY_FILE", "TLS_MIN_VERSION", "TLS_PIN_SHA256", "TLS_INSECURE_SKIP_VERIFY",
+		"HTTP_TIMEOUT", "HTTP_DIAL_TIMEOUT", "HTTP_IDLE_TIMEOUT", "HTTP_KEEPALIVE", "HTTP_MAX_IDLE_CONNS", "HTTP_DISABLE_KEEPALIVES", "HTTP_PROXY_URL", "HTTP2",
+		"API_KEY", "API_KEY_FILE", "API_KEY_COMMAND", "API_SIGNING",
+		"COMPRESSION", "COMPRESSION_MIN_BYTES",
+		"SEND_RATE", "SEND_BURST", "SEND_JITTER",
+		"PEER_SUMMARY_INTERVAL", "PEER_FLAP_THRESHOLD", "CONSENSUS_SUMMARY_INTERVAL", "CONSENSUS_STALL_AFTER", "REWARD_SCHEDULE_FILE",
+		"STATUS_ADDR", "HEARTBEAT_INTERVAL", "WATCHDOG_STALL_AFTER", "WATCHDOG_SILENT_AFTER", "INCIDENT_WINDOW",
+	}
+	secretEnvVars = map[string]bool{"API_KEY": true}
+)
+
+func EnvVars() []string {
+	return append([]string(nil), envVars...)
+}
+
+func IsSecretEnv(name string) bool {
+	return secretEnvVars[name]
+}
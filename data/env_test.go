@@ -0,0 +1,51 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestEnvVars(t *testing.T) {
+	// setup test variables
+	var known = make(map[string]bool)
+	var read = regexp.MustCompile(`(?:Getenv|LookupEnv)\("([A-Z][A-Z0-9_]*)"\)|\{"([A-Z][A-Z0-9_]*)", &`)
+
+	for _, k := range EnvVars() {
+		known[k] = true
+	}
+
+	readme, err := os.ReadFile(filepath.Join("..", "README.md"))
+	if err != nil {
+		t.Fatalf("os.ReadFile() returned error: %v", err)
+	}
+
+	// test every env var read by the client listed, ie config show
+	for _, dir := range []string{".", "../handlers", "../cmd"} {
+		fps, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+		for _, fp := range fps {
+			if strings.HasSuffix(fp, "_test.go") {
+				continue
+			}
+			b, err := os.ReadFile(fp)
+			if err != nil {
+				t.Fatalf("os.ReadFile() returned error: %v", err)
+			}
+			for _, m := range read.FindAllStringSubmatch(string(b), -1) {
+				name := m[1] + m[2]
+				if !known[name] {
+					t.Fatalf("data.EnvVars() missing: %v, read in %v", name, fp)
+				}
+			}
+		}
+	}
+
+	// test every listed env var documented
+	for k := range known {
+		if !strings.Contains(string(readme), k) {
+			t.Fatalf("data.EnvVars() lists: %v, not in README", k)
+		}
+	}
+}
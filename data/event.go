@@ -0,0 +1,89 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	eventsServiceURL = fmt.Sprintf("%s/stats/events", apiAddr)
+)
+
+var (
+	sinksMu sync.RWMutex
+	sinks   []Sink
+)
+
+type Event struct {
+	Type      string      `json:"type"`
+	Addr      string      `json:"address"`
+	Data      interface{} `json:"data"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type Sink interface {
+	Send(e *Event) error
+}
+
+type ServerSink struct{}
+
+type PrintSink struct{}
+
+func NewEvent(typ string, data interface{}) *Event {
+	return &Event{
+		Type:      typ,
+		Addr:      getAddr(),
+		Data:      data,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func AddSink(s Sink) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+
+	sinks = append(sinks, s)
+}
+
+func ResetSinks() {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+
+	sinks = nil
+}
+
+func PublishEvent(e *Event) error {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+
+	// fan out to every sink, keep first error
+	var first error
+	for _, s := range sinks {
+		if err := s.Send(e); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+func (ServerSink) Send(e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	return postData(Job{URL: eventsServiceURL, Body: b})
+}
+
+func (PrintSink) Send(e *Event) error {
+	b, err := json.Marshal(e.Data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("EdgeStats event %s: %s\n", e.Type, b)
+	return nil
+}
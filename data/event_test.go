@@ -0,0 +1,66 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testSink struct {
+	events []*Event
+	err    error
+}
+
+func (s *testSink) Send(e *Event) error {
+	s.events = append(s.events, e)
+	return s.err
+}
+
+func TestPublishEvent(t *testing.T) {
+	defer ResetSinks()
+
+	// setup test variables
+	var a = &testSink{err: errors.New("sink down")}
+	var b = &testSink{}
+
+	ResetSinks()
+	AddSink(a)
+	AddSink(b)
+
+	// test event fanned out despite failing sink
+	e := NewEvent("node_state", map[string]string{"state": "stalled"})
+	if err := PublishEvent(e); err == nil {
+		t.Fatalf("data.PublishEvent() returned: %v, wanted error", err)
+	}
+
+	if len(a.events) != 1 || len(b.events) != 1 || b.events[0] != e {
+		t.Fatalf("data.PublishEvent() sent: %v, %v", a.events, b.events)
+	}
+}
+
+func TestServerSink(t *testing.T) {
+	// setup test variables
+	var got Event
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf, _ := io.ReadAll(r.Body)
+		json.Unmarshal(buf, &got)
+	}))
+	defer srv.Close()
+
+	url := eventsServiceURL
+	eventsServiceURL = srv.URL + "/stats/events"
+	defer func() { eventsServiceURL = url }()
+
+	// test event posted to server
+	if err := (ServerSink{}).Send(NewEvent("node_state", "stalled")); err != nil {
+		t.Fatalf("data.ServerSink.Send() returned error: %v", err)
+	}
+
+	if got.Type != "node_state" || got.Data != "stalled" {
+		t.Fatalf("data.ServerSink.Send() sent: %+v", got)
+	}
+}
@@ -6,21 +6,79 @@ const (
 	ErrFilter = iota
 	UMFilter
 	P2PFilter
+	NSFilter
+	CSFilter
+	LevelFilter
 )
 
 var (
-	uptime = []byte("[uptime miner")
-	p2p    = []byte("[p2p]")
+	uptime    = []byte("[uptime miner")
+	p2p       = []byte("[p2p]")
+	netsync   = []byte("[netsync]")
+	consensus = []byte("[consensus]")
 )
 
 func Filter(b []byte) int {
-	// filter log by category
+	// filter log by category, warnings and errors without one become incidents
 	switch true {
 	case bytes.Contains(b, uptime):
 		return UMFilter
 	case bytes.Contains(b, p2p):
 		return P2PFilter
+	case bytes.Contains(b, netsync):
+		return NSFilter
+	case bytes.Contains(b, consensus):
+		return CSFilter
+	case hasWarnLevel(b):
+		return LevelFilter
 	default:
 		return ErrFilter
 	}
 }
+
+func Filters(b []byte) []int {
+	// category record plus incident for warnings and errors, ie both sent
+	i := Filter(b)
+	if i == ErrFilter {
+		return nil
+	}
+	if i != LevelFilter && hasWarnLevel(b) {
+		return []int{i, LevelFilter}
+	}
+	return []int{i}
+}
+
+func FilterName(i int) string {
+	switch i {
+	case UMFilter:
+		return "uptime"
+	case P2PFilter:
+		return "p2p"
+	case NSFilter:
+		return "netsync"
+	case CSFilter:
+		return "consensus"
+	case LevelFilter:
+		return "incident"
+	default:
+		return "none"
+	}
+}
+
+func NewParser(i int) Parser {
+	// new record for filter category
+	switch i {
+	case UMFilter:
+		return NewUMBroadcast()
+	case P2PFilter:
+		return NewP2PNumPeers()
+	case NSFilter:
+		return NewNSProgress()
+	case CSFilter:
+		return NewCSProgress()
+	case LevelFilter:
+		return NewIncident()
+	default:
+		return nil
+	}
+}
@@ -1,13 +1,19 @@
 package data
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 var (
-	UMLogExample  = []byte("... [uptime miner] ...")
-	NSLogExample  = []byte("... [netsync] ...")
-	P2PLogExample = []byte("... [p2p] ...")
-	ECLogExample  = []byte(`{"user": "Anonymous", ...`)
-	ErrLogExample = []byte("... [unmatched] ...")
+	UMLogExample          = []byte("... [uptime miner] ...")
+	NSLogExample          = []byte("... [netsync] ...")
+	P2PLogExample         = []byte("... [p2p] ...")
+	CSLogExample          = []byte("... [consensus] ...")
+	WarnLogExample        = []byte("[2021-08-28 09:00:26.951] [info] [ThetaEdgeLauncher] [2021-08-28 09:00:26]  WARN [p2p] ...")
+	LauncherErrLogExample = []byte("[2021-08-28 09:00:26.951] [error] Failed to start node")
+	ECLogExample          = []byte(`{"user": "Anonymous", ...`)
+	ErrLogExample         = []byte("... [unmatched] ...")
 )
 
 func TestFilter(t *testing.T) {
@@ -34,6 +40,42 @@ func TestFilter(t *testing.T) {
 		t.Fatalf("data.Filter() returned: %v, wanted: %v", got, want)
 	}
 
+	// test netsync log
+	log = NSLogExample
+	want = NSFilter
+	got = Filter(log)
+
+	if got != want {
+		t.Fatalf("data.Filter() returned: %v, wanted: %v", got, want)
+	}
+
+	// test consensus log
+	log = CSLogExample
+	want = CSFilter
+	got = Filter(log)
+
+	if got != want {
+		t.Fatalf("data.Filter() returned: %v, wanted: %v", got, want)
+	}
+
+	// test node warning log, category first
+	log = WarnLogExample
+	want = P2PFilter
+	got = Filter(log)
+
+	if got != want {
+		t.Fatalf("data.Filter() returned: %v, wanted: %v", got, want)
+	}
+
+	// test launcher error log
+	log = LauncherErrLogExample
+	want = LevelFilter
+	got = Filter(log)
+
+	if got != want {
+		t.Fatalf("data.Filter() returned: %v, wanted: %v", got, want)
+	}
+
 	// test no match
 	log = ErrLogExample
 	want = ErrFilter
@@ -43,3 +85,54 @@ func TestFilter(t *testing.T) {
 		t.Fatalf("data.Filter() returned: %v, wanted: %v", got, want)
 	}
 }
+
+func TestFilters(t *testing.T) {
+	// setup test variables
+	tests := []struct {
+		log  []byte
+		want []int
+	}{
+		{UMLogExample, []int{UMFilter}},
+		{WarnLogExample, []int{P2PFilter, LevelFilter}},
+		{LauncherErrLogExample, []int{LevelFilter}},
+		{ErrLogExample, nil},
+	}
+
+	// test warnings with category emit both records
+	for _, tc := range tests {
+		if got := Filters(tc.log); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("data.Filters(%s) returned: %v, wanted: %v", tc.log, got, tc.want)
+		}
+	}
+}
+
+func TestNewParser(t *testing.T) {
+	// test parser per category
+	for i, want := range map[int]Parser{
+		UMFilter:    NewUMBroadcast(),
+		P2PFilter:   NewP2PNumPeers(),
+		NSFilter:    NewNSProgress(),
+		CSFilter:    NewCSProgress(),
+		LevelFilter: NewIncident(),
+	} {
+		got := NewParser(i)
+		if reflect.TypeOf(got) != reflect.TypeOf(want) {
+			t.Fatalf("data.NewParser(%d) returned: %T, wanted: %T", i, got, want)
+		}
+	}
+
+	// test no match filter
+	if got := NewParser(ErrFilter); got != nil {
+		t.Fatalf("data.NewParser() returned: %T, wanted: nil", got)
+	}
+}
+
+func TestFilterName(t *testing.T) {
+	// test known and unknown categories
+	if got := FilterName(NSFilter); got != "netsync" {
+		t.Fatalf("data.FilterName() returned: %v, wanted: %v", got, "netsync")
+	}
+	if got := FilterName(ErrFilter); got != "none" {
+		t.Fatalf("data.FilterName() returned: %v, wanted: %v", got, "none")
+	}
+}
@@ -0,0 +1,313 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultIncidentWindow = time.Minute
+	maxIncidentKeys       = 256
+	otherIncidentMessage  = "(other)"
+)
+
+var (
+	incidentServiceURL = fmt.Sprintf("%s/stats/uptimes/incidents", apiAddr)
+	incidents          = newIncidentTracker(defaultIncidentWindow, maxIncidentKeys)
+)
+
+type Incident struct {
+	Addr         string     `json:"address"`
+	Level        string     `json:"level"`
+	Component    string     `json:"component"`
+	Message      string     `json:"message"`
+	Count        int        `json:"count"`
+	FirstAt      time.Time  `json:"first_at"`
+	LastAt       time.Time  `json:"last_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LauncherTime *time.Time `json:"launcher_time,omitempty"`
+	NodeTime     *time.Time `json:"node_time,omitempty"`
+	TimeSource   string     `json:"time_source"`
+	aggregate    bool       // counts flushed after window
+}
+
+type incidentState struct {
+	sample  Incident  // newest line seen, used for flushed records
+	sentAt  time.Time // log time of last forwarded record
+	pending int       // lines suppressed since sentAt
+	firstAt time.Time // oldest suppressed line
+	lastAt  time.Time // newest suppressed line
+}
+
+type incidentTracker struct {
+	mu       sync.Mutex
+	window   time.Duration
+	max      int
+	open     map[string]*incidentState
+	last     time.Time // newest log time observed
+	lastWall time.Time // wall time newest log time observed at
+}
+
+func NewIncident() *Incident {
+	return &Incident{}
+}
+
+func IncidentWindowFromEnv() (time.Duration, error) {
+	v := os.Getenv("INCIDENT_WINDOW")
+	if v == "" {
+		return defaultIncidentWindow, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		s := fmt.Sprintf("invalid INCIDENT_WINDOW: %s", v)
+		return 0, errors.New(s)
+	}
+
+	return d, nil
+}
+
+func SetIncidentWindow(d time.Duration) {
+	incidents = newIncidentTracker(d, maxIncidentKeys)
+}
+
+func RunIncidents(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			sendIncidents(ctx, incidents.flush(incidents.logNow(now), false))
+		}
+	}
+}
+
+func FlushIncidents(ctx context.Context) {
+	// send counts still held back, ie on shutdown
+	sendIncidents(ctx, incidents.flush(incidents.logNow(time.Now()), true))
+}
+
+func (inc *Incident) ToJSON() ([]byte, error) {
+	return json.Marshal(inc)
+}
+
+func (inc *Incident) ID() string {
+	// node time has second precision, message, span and count tell records apart
+	h := fnv.New64a()
+	h.Write([]byte(inc.Message))
+
+	var lt int64
+	if inc.LauncherTime != nil {
+		lt = inc.LauncherTime.UnixNano()
+	}
+
+	kind := "line"
+	if inc.aggregate {
+		kind = "count"
+	}
+
+	return fmt.Sprintf("incident:%s:%s:%s:%x:%s:%d:%d:%d:%d", inc.Addr, inc.Level, inc.Component, h.Sum64(),
+		kind, inc.FirstAt.UnixNano(), inc.LastAt.UnixNano(), lt, inc.Count)
+}
+
+func (inc *Incident) Parse(b []byte) error {
+	level, component, msg := splitLevel(b)
+	if level == "" {
+		return errNoMatch
+	}
+
+	lt, err := parseTimes(b)
+	if err != nil {
+		return err
+	}
+	t, ts := lt.createdAt()
+
+	// return error if node not yet bootstrapped with address
+	addr := getAddr()
+	if addr == "" {
+		return errNoAddr
+	}
+
+	inc.Addr = addr
+	inc.Level = level
+	inc.Component = component
+	inc.Message = msg
+	inc.Count = 1
+	inc.FirstAt = t
+	inc.LastAt = t
+	inc.CreatedAt = t
+	inc.LauncherTime = optTime(lt.launcher)
+	inc.NodeTime = optTime(lt.node)
+	inc.TimeSource = ts
+	markLogTime(t)
+
+	return nil
+}
+
+func (inc *Incident) Sample() bool {
+	// forward first line per window, count the rest, flushed counts always sent
+	if inc.aggregate {
+		return true
+	}
+	return incidents.observe(inc)
+}
+
+func newIncidentTracker(window time.Duration, max int) *incidentTracker {
+	return &incidentTracker{
+		window: window,
+		max:    max,
+		open:   make(map[string]*incidentState),
+	}
+}
+
+func (it *incidentTracker) observe(inc *Incident) bool {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	// group lines differing only by heights, hashes and addresses
+	key := inc.Level + ":" + inc.Component + ":" + normalizeMessage(inc.Message)
+	st, ok := it.open[key]
+	if !ok && len(it.open) >= it.max {
+		key = inc.Level + ":" + inc.Component + ":" + otherIncidentMessage
+		st, ok = it.open[key]
+	}
+	if !ok {
+		st = &incidentState{}
+		it.open[key] = st
+	}
+
+	t := inc.CreatedAt
+	if !ok || !t.Before(st.sample.LastAt) {
+		st.sample = *inc
+	}
+
+	// log time clock, ie backfill flushed by log not wall time
+	if t.After(it.last) {
+		it.last = t
+		it.lastWall = time.Now()
+	}
+
+	// suppress within window either side of last forwarded, ie also older lines replayed
+	if d := t.Sub(st.sentAt); ok && d < it.window && d > -it.window {
+		if st.pending == 0 || t.Before(st.firstAt) {
+			st.firstAt = t
+		}
+		if st.pending == 0 || t.After(st.lastAt) {
+			st.lastAt = t
+		}
+		st.pending++
+		return false
+	}
+
+	// forward with lines suppressed since last record
+	if st.pending > 0 {
+		inc.Count += st.pending
+		if st.firstAt.Before(inc.FirstAt) {
+			inc.FirstAt = st.firstAt
+		}
+		if st.lastAt.After(inc.LastAt) {
+			inc.LastAt = st.lastAt
+		}
+	}
+	st.sentAt = t
+	st.pending = 0
+
+	return true
+}
+
+func (it *incidentTracker) logNow(wall time.Time) time.Time {
+	// newest log time, advanced by wall time since, ie quiet log
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	if it.last.IsZero() {
+		return wall.UTC()
+	}
+	if d := wall.Sub(it.lastWall); d > 0 {
+		return it.last.Add(d)
+	}
+
+	return it.last
+}
+
+func (it *incidentTracker) flush(now time.Time, all bool) []*Incident {
+	// now is log time, see logNow
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	var out []*Incident
+	for key, st := range it.open {
+		// forget quiet incidents, bounds tracked keys
+		if st.pending == 0 {
+			if now.Sub(st.sentAt) > 10*it.window {
+				delete(it.open, key)
+			}
+			continue
+		}
+		if !all && now.Sub(st.sentAt) < it.window {
+			continue
+		}
+
+		inc := st.sample
+		inc.Count = st.pending
+		inc.FirstAt = st.firstAt
+		inc.LastAt = st.lastAt
+		inc.aggregate = true
+		out = append(out, &inc)
+
+		st.sentAt = inc.LastAt
+		st.pending = 0
+	}
+
+	return out
+}
+
+func sendIncidents(ctx context.Context, out []*Incident) {
+	for _, inc := range out {
+		j, err := Encode(inc)
+		if err != nil {
+			continue
+		}
+		if err := Dispatch(ctx, j); err != nil {
+			fmt.Printf("Warning: incident not sent: %v\n", err)
+		}
+	}
+}
+
+func normalizeMessage(msg string) string {
+	// replace word runs holding digits, ie heights and hex, with #
+	var b []byte
+	for i := 0; i < len(msg); {
+		if !isWordChar(msg[i]) {
+			b = append(b, msg[i])
+			i++
+			continue
+		}
+
+		e := i
+		digit := false
+		for e < len(msg) && isWordChar(msg[e]) {
+			if msg[e] >= '0' && msg[e] <= '9' {
+				digit = true
+			}
+			e++
+		}
+		if digit {
+			b = append(b, '#')
+		} else {
+			b = append(b, msg[i:e]...)
+		}
+		i = e
+	}
+
+	return string(b)
+}
@@ -0,0 +1,272 @@
+package data
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	NodeWarnEx    = []byte("[2021-08-28 09:17:11.831] [info] [ThetaEdgeLauncher] [2021-08-28 09:17:11]  WARN [p2p] Failed to connect to peer, peer: 0x2e833968e5")
+	NodeWarn2Ex   = []byte("[2021-08-28 09:17:41.831] [info] [ThetaEdgeLauncher] [2021-08-28 09:17:41]  WARN [p2p] Failed to connect to peer, peer: 0x9ab1c2d3e4")
+	NodeWarn3Ex   = []byte("[2021-08-28 09:18:21.831] [info] [ThetaEdgeLauncher] [2021-08-28 09:18:21]  WARN [p2p] Failed to connect to peer, peer: 0x2e833968e5")
+	NodeErrEx     = []byte("[2021-08-28 09:17:12.831] [info] [ThetaEdgeLauncher] [2021-08-28 09:17:12]  ERRO [uptime miner] Failed to broadcast vote")
+	LauncherErrEx = []byte("[2021-08-28 09:17:13.831] [error] Edge node process exited, code: 1")
+)
+
+func TestNewIncident(t *testing.T) {
+	want := &Incident{}
+	got := NewIncident()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("data.NewIncident() returned: %v, wanted: %v", got, want)
+	}
+}
+
+func TestIncidentWindowFromEnv(t *testing.T) {
+	// test default window
+	os.Unsetenv("INCIDENT_WINDOW")
+	if got, err := IncidentWindowFromEnv(); err != nil || got != defaultIncidentWindow {
+		t.Fatalf("data.IncidentWindowFromEnv() returned: %v, %v, wanted: %v", got, err, defaultIncidentWindow)
+	}
+
+	// test configured window
+	os.Setenv("INCIDENT_WINDOW", "5m")
+	defer os.Unsetenv("INCIDENT_WINDOW")
+	if got, err := IncidentWindowFromEnv(); err != nil || got != 5*time.Minute {
+		t.Fatalf("data.IncidentWindowFromEnv() returned: %v, %v, wanted: %v", got, err, 5*time.Minute)
+	}
+
+	// test invalid window
+	os.Setenv("INCIDENT_WINDOW", "-1s")
+	if got, err := IncidentWindowFromEnv(); err == nil {
+		t.Fatalf("data.IncidentWindowFromEnv() returned: %v, wanted error", got)
+	}
+}
+
+func TestIncidentParse(t *testing.T) {
+	// setup test variables
+	var log []byte
+	var got = NewIncident()
+	var want = NewIncident()
+	var lt logTimes
+	var err error
+
+	// sim bootstrap address, fresh tracker
+	setAddr("0x8d25fa2e7d")
+	defer setAddr("")
+	SetIncidentWindow(time.Minute)
+	defer SetIncidentWindow(defaultIncidentWindow)
+
+	// test parse node warning
+	log = NodeWarnEx
+	lt, _ = parseTimes(log)
+	want = &Incident{
+		Addr:         "0x8d25fa2e7d",
+		Level:        LevelWarn,
+		Component:    "p2p",
+		Message:      "Failed to connect to peer, peer: 0x2e833968e5",
+		Count:        1,
+		FirstAt:      lt.node,
+		LastAt:       lt.node,
+		CreatedAt:    lt.node,
+		LauncherTime: optTime(lt.launcher),
+		NodeTime:     optTime(lt.node),
+		TimeSource:   NodeTimeSource,
+	}
+
+	if err = got.Parse(log); err != nil {
+		t.Fatalf("data.IncidentParse() returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("data.IncidentParse() returned: %v, wanted: %v", got, want)
+	}
+
+	// test parse alone never counts a line, ie dry run and validate
+	got = NewIncident()
+	if err = got.Parse(NodeWarn2Ex); err != nil {
+		t.Fatalf("data.IncidentParse() returned error: %v", err)
+	}
+
+	if got.Count != 1 || len(incidents.open) != 0 {
+		t.Fatalf("data.IncidentParse() returned: %v, tracked: %v, wanted untracked", got.Count, len(incidents.open))
+	}
+
+	// test parse node error, tracked apart from warnings
+	got = NewIncident()
+	if err = got.Parse(NodeErrEx); err != nil {
+		t.Fatalf("data.IncidentParse() returned error: %v", err)
+	}
+
+	if got.Level != LevelError || got.Component != "uptime miner" {
+		t.Fatalf("data.IncidentParse() returned: %v, %v, wanted: %v, %v", got.Level, got.Component, LevelError, "uptime miner")
+	}
+
+	// test parse launcher error
+	got = NewIncident()
+	if err = got.Parse(LauncherErrEx); err != nil {
+		t.Fatalf("data.IncidentParse() returned error: %v", err)
+	}
+
+	if got.Level != LevelError || got.Component != launcherComponent || got.Message != "Edge node process exited, code: 1" {
+		t.Fatalf("data.IncidentParse() returned: %v, wanted launcher error", got)
+	}
+
+	// test no match error
+	if err = got.Parse(P2PNumPeersEx); err != errNoMatch {
+		t.Fatalf("data.IncidentParse() returned: %v, wanted error: %v", err, errNoMatch)
+	}
+}
+
+func TestIncidentEncode(t *testing.T) {
+	// sim bootstrap address, fresh tracker
+	setAddr("0x8d25fa2e7d")
+	defer setAddr("")
+	SetIncidentWindow(time.Minute)
+	defer SetIncidentWindow(defaultIncidentWindow)
+
+	encode := func(b []byte) (*Incident, error) {
+		inc := NewIncident()
+		if err := inc.Parse(b); err != nil {
+			return inc, err
+		}
+		j, err := Encode(inc)
+		dedup.release(j.Key)
+		return inc, err
+	}
+
+	// test first line sent
+	if _, err := encode(NodeWarnEx); err != nil {
+		t.Fatalf("data.Encode() returned error: %v", err)
+	}
+
+	// test same incident with other peer counted within window, preview not counted
+	inc := NewIncident()
+	inc.Parse(NodeWarn2Ex)
+	Preview(inc)
+	if _, err := encode(NodeWarn2Ex); err != errThrottled {
+		t.Fatalf("data.Encode() returned: %v, wanted error: %v", err, errThrottled)
+	}
+
+	// test forwarded after window with suppressed count
+	got, err := encode(NodeWarn3Ex)
+	if err != nil {
+		t.Fatalf("data.Encode() returned error: %v", err)
+	}
+
+	first, _ := parseTimes(NodeWarn2Ex)
+	if got.Count != 2 || !got.FirstAt.Equal(first.node) {
+		t.Fatalf("data.Encode() returned: %v, %v, wanted: %v, %v", got.Count, got.FirstAt, 2, first.node)
+	}
+}
+
+func TestIncidentTrackerFlush(t *testing.T) {
+	it := newIncidentTracker(time.Minute, 2)
+	t0 := time.Date(2021, 8, 28, 9, 0, 0, 0, time.UTC)
+	inc := func(msg string, t time.Time) *Incident {
+		return &Incident{Level: LevelError, Component: "p2p", Message: msg, Count: 1, FirstAt: t, LastAt: t, CreatedAt: t}
+	}
+
+	// test first forwarded, repeats held back
+	if !it.observe(inc("dial 0x01 failed", t0)) {
+		t.Fatalf("data.incidentTracker.observe() returned: false, wanted: true")
+	}
+	for i := 1; i <= 3; i++ {
+		if it.observe(inc("dial 0x02 failed", t0.Add(time.Duration(i)*time.Second))) {
+			t.Fatalf("data.incidentTracker.observe() returned: true, wanted: false")
+		}
+	}
+
+	// test nothing flushed within window
+	if got := it.flush(t0.Add(30*time.Second), false); len(got) != 0 {
+		t.Fatalf("data.incidentTracker.flush() returned: %v, wanted none", got)
+	}
+
+	// test held back count flushed after window
+	got := it.flush(t0.Add(time.Minute), false)
+	if len(got) != 1 || got[0].Count != 3 || !got[0].FirstAt.Equal(t0.Add(time.Second)) || got[0].Message != "dial 0x02 failed" {
+		t.Fatalf("data.incidentTracker.flush() returned: %+v, wanted count: 3", got)
+	}
+
+	// test distinct messages beyond max grouped as other
+	it.observe(inc("peer banned", t0))
+	if !it.observe(inc("disk full", t0)) {
+		t.Fatalf("data.incidentTracker.observe() returned: false, wanted: true")
+	}
+	if it.observe(inc("db closed", t0)) {
+		t.Fatalf("data.incidentTracker.observe() returned: true, wanted: false")
+	}
+
+	// test older lines replayed within window counted too
+	if it.observe(inc("dial 0x03 failed", t0.Add(time.Minute-time.Second))) {
+		t.Fatalf("data.incidentTracker.observe() returned: true, wanted: false")
+	}
+	if !it.observe(inc("dial 0x04 failed", t0.Add(-time.Hour))) {
+		t.Fatalf("data.incidentTracker.observe() returned: false, wanted: true")
+	}
+
+	// test flush all on shutdown
+	if got := it.flush(t0, true); len(got) != 1 || got[0].Count != 1 {
+		t.Fatalf("data.incidentTracker.flush() returned: %+v, wanted one", got)
+	}
+}
+
+func TestIncidentID(t *testing.T) {
+	// setup test variables
+	var t0 = time.Date(2021, 8, 28, 9, 0, 0, 0, time.UTC)
+	var a = &Incident{Addr: "0x8d25fa2e7d", Level: LevelWarn, Component: "p2p", Message: "dial 0x01 failed", Count: 1, FirstAt: t0, LastAt: t0, CreatedAt: t0}
+
+	// test other message in same second differs
+	b := *a
+	b.Message = "peer banned"
+	if a.ID() == b.ID() {
+		t.Fatalf("data.Incident.ID() returned: %v for both messages", a.ID())
+	}
+
+	// test count flushed in same second as forwarded line differs
+	it := newIncidentTracker(time.Minute, 8)
+	fwd := *a
+	it.observe(&fwd)
+	rep := *a
+	rep.Message = "dial 0x02 failed"
+	it.observe(&rep)
+
+	got := it.flush(t0.Add(time.Minute), false)
+	if len(got) != 1 || got[0].ID() == fwd.ID() {
+		t.Fatalf("data.Incident.ID() returned: %v, same as forwarded: %v", got, fwd.ID())
+	}
+}
+
+func TestIncidentTrackerLogNow(t *testing.T) {
+	// setup test variables
+	var it = newIncidentTracker(time.Minute, 8)
+	var t0 = time.Date(2021, 8, 28, 9, 0, 0, 0, time.UTC)
+
+	// test backfilled repeats not flushed by wall clock
+	it.observe(&Incident{Level: LevelError, Component: "p2p", Message: "dial failed", Count: 1, FirstAt: t0, LastAt: t0, CreatedAt: t0})
+	it.observe(&Incident{Level: LevelError, Component: "p2p", Message: "dial failed", Count: 1, FirstAt: t0, LastAt: t0, CreatedAt: t0.Add(time.Second)})
+
+	now := time.Now()
+	if got := it.flush(it.logNow(now), false); len(got) != 0 {
+		t.Fatalf("data.incidentTracker.flush() returned: %v, wanted none", got)
+	}
+
+	// test log time advanced by wall time once log quiet
+	if got := it.logNow(now.Add(2 * time.Minute)); got.Before(t0.Add(2 * time.Minute)) {
+		t.Fatalf("data.incidentTracker.logNow() returned: %v, wanted after: %v", got, t0.Add(2*time.Minute))
+	}
+	if got := it.flush(it.logNow(now.Add(2*time.Minute)), false); len(got) != 1 {
+		t.Fatalf("data.incidentTracker.flush() returned: %v, wanted one", got)
+	}
+}
+
+func TestNormalizeMessage(t *testing.T) {
+	got := normalizeMessage("Failed to connect to peer, peer: 0x2e833968e5, height: 11759201")
+	want := "Failed to connect to peer, peer: #, height: #"
+
+	if got != want {
+		t.Fatalf("data.normalizeMessage() returned: %v, wanted: %v", got, want)
+	}
+}
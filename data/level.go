@@ -0,0 +1,85 @@
+package data
+
+import "bytes"
+
+const (
+	LevelWarn  = "warn"
+	LevelError = "error"
+	LevelFatal = "fatal"
+
+	launcherComponent = "launcher"
+)
+
+var (
+	// node levels, ie logrus 4 letter and full names
+	warnLevels = [][]byte{
+		[]byte(" WARN "), []byte(" WARNING "),
+		[]byte(" ERRO "), []byte(" ERROR "),
+		[]byte(" FATA "), []byte(" FATAL "),
+		[]byte(" PANI "), []byte(" PANIC "),
+	}
+	// launcher levels
+	launcherLevels = [][]byte{
+		[]byte("] [warn] "), []byte("] [warning] "), []byte("] [error] "),
+	}
+)
+
+func hasWarnLevel(b []byte) bool {
+	for _, l := range warnLevels {
+		if bytes.Contains(b, l) {
+			return true
+		}
+	}
+	for _, l := range launcherLevels {
+		if bytes.Contains(b, l) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func splitLevel(b []byte) (level, component, msg string) {
+	// node level first, launcher may wrap node lines
+	for _, l := range warnLevels {
+		i := bytes.Index(b, l)
+		if i < 0 {
+			continue
+		}
+
+		rest := bytes.TrimSpace(b[i+len(l):])
+		if len(rest) > 0 && rest[0] == '[' {
+			if e := bytes.IndexByte(rest, ']'); e > 0 {
+				component = string(rest[1:e])
+				rest = bytes.TrimSpace(rest[e+1:])
+			}
+		}
+
+		return normalizeLevel(l), component, string(rest)
+	}
+
+	for _, l := range launcherLevels {
+		i := bytes.Index(b, l)
+		if i < 0 {
+			continue
+		}
+
+		rest := bytes.TrimSpace(b[i+len(l):])
+		return normalizeLevel(l), launcherComponent, string(rest)
+	}
+
+	return "", "", ""
+}
+
+func normalizeLevel(l []byte) string {
+	// map logrus and launcher names, ie " ERRO " and "] [error] "
+	l = bytes.ToLower(bytes.Trim(l, " []"))
+	switch true {
+	case bytes.HasPrefix(l, []byte("warn")):
+		return LevelWarn
+	case bytes.HasPrefix(l, []byte("erro")):
+		return LevelError
+	default:
+		return LevelFatal
+	}
+}
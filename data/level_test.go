@@ -0,0 +1,30 @@
+package data
+
+import "testing"
+
+func TestSplitLevel(t *testing.T) {
+	// setup test variables
+	var tests = []struct {
+		log       []byte
+		level     string
+		component string
+		msg       string
+	}{
+		{NodeWarnEx, LevelWarn, "p2p", "Failed to connect to peer, peer: 0x2e833968e5"},
+		{NodeErrEx, LevelError, "uptime miner", "Failed to broadcast vote"},
+		{LauncherErrEx, LevelError, launcherComponent, "Edge node process exited, code: 1"},
+		{[]byte("[2021-08-28 09:17:11.831] [info] [ThetaEdgeLauncher] [2021-08-28 09:17:11]  PANIC [consensus] invalid epoch"), LevelFatal, "consensus", "invalid epoch"},
+		{OtherLogEx, "", "", ""},
+	}
+
+	// test node, launcher and unknown levels
+	for _, tt := range tests {
+		level, component, msg := splitLevel(tt.log)
+		if level != tt.level || component != tt.component || msg != tt.msg {
+			t.Fatalf("data.splitLevel(%s) returned: %q, %q, %q, wanted: %q, %q, %q", tt.log, level, component, msg, tt.level, tt.component, tt.msg)
+		}
+		if got := hasWarnLevel(tt.log); got != (tt.level != "") {
+			t.Fatalf("data.hasWarnLevel(%s) returned: %v", tt.log, got)
+		}
+	}
+}
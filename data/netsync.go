@@ -0,0 +1,122 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	nsSampleEvery = 30 * time.Second
+	nsSyncedLag   = 2 // blocks behind still counted as synced
+)
+
+var (
+	nsProgressServiceURL = fmt.Sprintf("%s/stats/uptimes/syncs", apiAddr)
+	nsThrottle           = newThrottle(nsSampleEvery)
+)
+
+var (
+	// height keys seen in node logs, ie received block and sync progress lines
+	nsLocalKeys   = [][]byte{[]byte("height"), []byte("currentHeight")}
+	nsNetworkKeys = [][]byte{[]byte("targetHeight")}
+)
+
+type NSProgress struct {
+	Addr          string     `json:"address"`
+	Height        int        `json:"height"`
+	NetworkHeight int        `json:"network_height"`
+	Lag           int        `json:"lag"`
+	Syncing       bool       `json:"syncing"`
+	CreatedAt     time.Time  `json:"created_at"`
+	LauncherTime  *time.Time `json:"launcher_time,omitempty"`
+	NodeTime      *time.Time `json:"node_time,omitempty"`
+	TimeSource    string     `json:"time_source"`
+}
+
+func NewNSProgress() *NSProgress {
+	return &NSProgress{}
+}
+
+func (ns *NSProgress) ToJSON() ([]byte, error) {
+	return json.Marshal(ns)
+}
+
+func (ns *NSProgress) ID() string {
+	return fmt.Sprintf("sync:%s:%d:%d", ns.Addr, ns.Height, ns.CreatedAt.UnixNano())
+}
+
+func (ns *NSProgress) Parse(b []byte) error {
+	if !bytes.Contains(b, netsync) {
+		return errNoMatch
+	}
+
+	var vh int
+	var vn int
+
+	// unknown keys ignored, netsync wording varies by node version
+	for k, v, rest := nextKeyValue(b); k != nil; k, v, rest = nextKeyValue(rest) {
+		switch true {
+		case matchesKey(k, nsLocalKeys):
+			n, err := atoi(v)
+			if err != nil {
+				return err
+			}
+			vh = n
+		case matchesKey(k, nsNetworkKeys):
+			n, err := atoi(v)
+			if err != nil {
+				return err
+			}
+			vn = n
+		}
+	}
+
+	// not a progress line, ie peer disconnected
+	if vh == 0 {
+		return errNoMatch
+	}
+
+	lt, err := parseTimes(b)
+	if err != nil {
+		return err
+	}
+	t, ts := lt.createdAt()
+
+	// return error if node not yet bootstrapped with address
+	addr := getAddr()
+	if addr == "" {
+		return errNoAddr
+	}
+
+	// sample progress, netsync logs every block while syncing
+	if !nsThrottle.allow(addr, t) {
+		return errThrottled
+	}
+
+	ns.Addr = addr
+	ns.Height = vh
+	ns.NetworkHeight = vn
+	ns.Lag = 0
+	if vn > vh {
+		ns.Lag = vn - vh
+	}
+	ns.Syncing = ns.Lag > nsSyncedLag
+	ns.CreatedAt = t
+	ns.LauncherTime = optTime(lt.launcher)
+	ns.NodeTime = optTime(lt.node)
+	ns.TimeSource = ts
+	markLogTime(t)
+
+	return nil
+}
+
+func matchesKey(k []byte, keys [][]byte) bool {
+	for _, key := range keys {
+		if bytes.Equal(k, key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	NSReceivedEx = []byte("[2021-08-28 09:17:11.831] [info] [ThetaEdgeLauncher] [2021-08-28 09:17:11]  INFO [netsync] Received block, height: 11759201")
+	NSProgressEx = []byte("[2021-08-28 09:17:42.831] [info] [ThetaEdgeLauncher] [2021-08-28 09:17:42]  INFO [netsync] Sync progress, currentHeight: 11759001, targetHeight: 11759201")
+)
+
+func TestNewNSProgress(t *testing.T) {
+	want := &NSProgress{}
+	got := NewNSProgress()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("data.NewNSProgress() returned: %v, wanted: %v", got, want)
+	}
+}
+
+func TestNSProgressParse(t *testing.T) {
+	// setup test variables
+	var log []byte
+	var got = NewNSProgress()
+	var want = NewNSProgress()
+	var lt logTimes
+	var err error
+
+	// sim bootstrap address, fresh sampling
+	setAddr("0x8d25fa2e7d")
+	defer setAddr("")
+	nsThrottle = newThrottle(nsSampleEvery)
+
+	// test parse received block, synced
+	log = NSReceivedEx
+	lt, _ = parseTimes(log)
+	want = &NSProgress{
+		Addr:         "0x8d25fa2e7d",
+		Height:       11759201,
+		CreatedAt:    lt.node,
+		LauncherTime: optTime(lt.launcher),
+		NodeTime:     optTime(lt.node),
+		TimeSource:   NodeTimeSource,
+	}
+
+	if err = got.Parse(log); err != nil {
+		t.Fatalf("data.NSProgressParse() returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("data.NSProgressParse() returned: %v, wanted: %v", got, want)
+	}
+
+	// test parse sync progress, syncing
+	log = NSProgressEx
+	got = NewNSProgress()
+	lt, _ = parseTimes(log)
+	want = &NSProgress{
+		Addr:          "0x8d25fa2e7d",
+		Height:        11759001,
+		NetworkHeight: 11759201,
+		Lag:           200,
+		Syncing:       true,
+		CreatedAt:     lt.node,
+		LauncherTime:  optTime(lt.launcher),
+		NodeTime:      optTime(lt.node),
+		TimeSource:    NodeTimeSource,
+	}
+
+	if err = got.Parse(log); err != nil {
+		t.Fatalf("data.NSProgressParse() returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("data.NSProgressParse() returned: %v, wanted: %v", got, want)
+	}
+
+	// test sampled within interval
+	if err = got.Parse(log); err != errThrottled {
+		t.Fatalf("data.NSProgressParse() returned: %v, wanted error: %v", err, errThrottled)
+	}
+
+	// test no height not a progress line
+	if err = got.Parse([]byte("[2021-08-28 09:17:11]  INFO [netsync] Peer disconnected")); err != errNoMatch {
+		t.Fatalf("data.NSProgressParse() returned: %v, wanted error: %v", err, errNoMatch)
+	}
+
+	// test no address bootstrapped error
+	setAddr("")
+	nsThrottle = newThrottle(time.Nanosecond)
+
+	if err = got.Parse(log); err == nil {
+		t.Fatalf("data.NSProgressParse() returned: %v, wanted error: %v", got, err)
+	}
+}
@@ -5,15 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"regexp"
-	"strconv"
+	"sync"
 	"time"
 )
 
-const (
-	p2pNumPeersRE = `(?m)(?P<key>\w+):\s+(?P<value>\w+)\,?`
-)
-
 const (
 	p2pErrFilter = iota
 	p2pNumPeersFilter
@@ -26,16 +21,27 @@ var (
 )
 
 var (
+	keyNumPeers           = numPeers
+	keySufficientNumPeers = []byte("sufficientNumPeers")
+)
+
+var (
+	nodeMu          sync.RWMutex // guards node state below
 	nodeAddr        string
 	nodePeers       int
 	sufficientPeers int
+	nodePeersAt     time.Time // log time of peers, ie held lines replayed later
 )
 
 type P2PNumPeers struct {
-	Addr            string    `json:"address"`
-	NumPeers        int       `json:"num_peers"`
-	SufficientPeers int       `json:"sufficient_peers"`
-	CreatedAt       time.Time `json:"created_at"`
+	Addr            string     `json:"address"`
+	NumPeers        int        `json:"num_peers"`
+	SufficientPeers int        `json:"sufficient_peers"`
+	CreatedAt       time.Time  `json:"created_at"`
+	LauncherTime    *time.Time `json:"launcher_time,omitempty"`
+	NodeTime        *time.Time `json:"node_time,omitempty"`
+	TimeSource      string     `json:"time_source"`
+	Issues          []string   `json:"issues,omitempty"` // set by validation
 }
 
 func NewP2PNumPeers() *P2PNumPeers {
@@ -46,56 +52,69 @@ func (p2p *P2PNumPeers) ToJSON() ([]byte, error) {
 	return json.Marshal(p2p)
 }
 
+func (p2p *P2PNumPeers) ID() string {
+	// node time has second precision, counts and launcher millis tell lines apart
+	var lt int64
+	if p2p.LauncherTime != nil {
+		lt = p2p.LauncherTime.UnixNano()
+	}
+	return fmt.Sprintf("peers:%s:%d:%d:%d:%d", p2p.Addr, p2p.CreatedAt.UnixNano(), lt, p2p.NumPeers, p2p.SufficientPeers)
+}
+
 func (p2p *P2PNumPeers) Parse(b []byte) error {
 	if filterP2PLogType(b) != p2pNumPeersFilter {
-		return errors.New("no match")
+		return errNoMatch
 	}
 
-	re := regexp.MustCompile(p2pNumPeersRE)
-	ml := re.FindAllSubmatch(b, -1)
-
 	var np int
 	var sp int
 
-	for _, i := range ml {
+	for k, v, rest := nextKeyValue(b); k != nil; k, v, rest = nextKeyValue(rest) {
 		switch true {
-		case bytes.Equal(i[1], []byte("numPeers")):
-			v, err := strconv.Atoi(string(i[2]))
+		case bytes.Equal(k, keyNumPeers):
+			n, err := atoi(v)
 			if err != nil {
 				return err
 			}
-			np = v
-		case bytes.Equal(i[1], []byte("sufficientNumPeers")):
-			v, err := strconv.Atoi(string(i[2]))
+			np = n
+		case bytes.Equal(k, keySufficientNumPeers):
+			n, err := atoi(v)
 			if err != nil {
 				return err
 			}
-			sp = v
+			sp = n
 		default:
-			s := fmt.Sprintf("error no match: %s, found: %s", i[1], i[2])
+			s := fmt.Sprintf("error no match: %s, found: %s", k, v)
 			return errors.New(s)
 		}
 	}
 
-	t, err := parseTime(b)
+	lt, err := parseTimes(b)
 	if err != nil {
 		return err
 	}
+	t, ts := lt.createdAt()
 
-	// populate numPeers and sufficientPeers variables
-	if err := setPeers(np, sp); err != nil {
+	// populate numPeers and sufficientPeers variables, newest line wins
+	if err := updatePeers(np, sp, t); err != nil {
 		return err
 	}
 
 	// return error if node not yet bootstrapped with address
-	if nodeAddr == "" {
-		return errors.New("no address bootstrapped")
+	addr := getAddr()
+	if addr == "" {
+		return errNoAddr
 	}
 
-	p2p.Addr = nodeAddr
+	p2p.Addr = addr
 	p2p.NumPeers = np
 	p2p.SufficientPeers = sp
 	p2p.CreatedAt = t
+	p2p.LauncherTime = optTime(lt.launcher)
+	p2p.NodeTime = optTime(lt.node)
+	p2p.TimeSource = ts
+	markLogTime(t)
+	observePeers(addr, t, np, sp)
 
 	return nil
 }
@@ -111,11 +130,47 @@ func filterP2PLogType(b []byte) int {
 }
 
 func setPeers(num, suff int) error {
+	nodeMu.Lock()
+	defer nodeMu.Unlock()
+
 	nodePeers = num
 	sufficientPeers = suff
+	nodePeersAt = time.Time{}
 	if sufficientPeers == 0 {
-		return errors.New("no peers bootstrapped")
+		return errNoPeers
 	}
 
 	return nil
 }
+
+func updatePeers(num, suff int, t time.Time) error {
+	nodeMu.Lock()
+	defer nodeMu.Unlock()
+
+	// older lines do not overwrite newer peers
+	if !t.Before(nodePeersAt) {
+		nodePeers = num
+		sufficientPeers = suff
+		nodePeersAt = t
+	}
+	if sufficientPeers == 0 {
+		return errNoPeers
+	}
+
+	return nil
+}
+
+func NodeInfo() (string, int, int) {
+	// bootstrapped address and peers, empty until seen in log
+	nodeMu.RLock()
+	defer nodeMu.RUnlock()
+
+	return nodeAddr, nodePeers, sufficientPeers
+}
+
+func getPeers() (int, int) {
+	nodeMu.RLock()
+	defer nodeMu.RUnlock()
+
+	return nodePeers, sufficientPeers
+}
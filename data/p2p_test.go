@@ -21,12 +21,32 @@ func TestNewP2PNumPeers(t *testing.T) {
 
 func TestP2PNumPeersToJSON(t *testing.T) {}
 
+func TestP2PNumPeersID(t *testing.T) {
+	// setup test variables
+	var ct = time.Date(2021, 8, 28, 9, 10, 32, 0, time.UTC)
+	var a = &P2PNumPeers{Addr: "0x8d25fa2e7d", NumPeers: 16, SufficientPeers: 16, CreatedAt: ct, LauncherTime: optTime(ct.Add(888 * time.Millisecond))}
+
+	// test same line same id
+	b := *a
+	if a.ID() != b.ID() {
+		t.Fatalf("data.P2PNumPeers.ID() returned: %v, wanted: %v", b.ID(), a.ID())
+	}
+
+	// test lines in same second with other counts or millis differ
+	b.NumPeers = 15
+	c := *a
+	c.LauncherTime = optTime(ct.Add(912 * time.Millisecond))
+	if a.ID() == b.ID() || a.ID() == c.ID() {
+		t.Fatalf("data.P2PNumPeers.ID() returned: %v, %v, %v, wanted distinct", a.ID(), b.ID(), c.ID())
+	}
+}
+
 func TestP2PNumPeersParse(t *testing.T) {
 	// setup test variables
 	var log []byte
 	var got = NewP2PNumPeers()
 	var want = NewP2PNumPeers()
-	var tt time.Time
+	var lt logTimes
 	var err error
 
 	// sim bootstrap address
@@ -34,13 +54,15 @@ func TestP2PNumPeersParse(t *testing.T) {
 
 	// test parse numPeers
 	log = P2PNumPeersEx
-	tt, _ = parseTime(log)
+	lt, _ = parseTimes(log)
 	want = &P2PNumPeers{
-		Addr: "0x8d25fa2e7d",
-		// Time:            tt,
+		Addr:            "0x8d25fa2e7d",
 		NumPeers:        16,
 		SufficientPeers: 16,
-		CreatedAt:       tt,
+		CreatedAt:       lt.node,
+		LauncherTime:    optTime(lt.launcher),
+		NodeTime:        optTime(lt.node),
+		TimeSource:      NodeTimeSource,
 	}
 
 	if err = got.Parse(log); err != nil {
@@ -108,3 +130,52 @@ func TestSetPeers(t *testing.T) {
 		t.Fatalf("data.setPeers() returned: %v, %v wanted: %v, %v", gotNodePeers, gotSufficientPeers, wantNodePeers, wantSufficientPeers)
 	}
 }
+
+func TestUpdatePeers(t *testing.T) {
+	// setup test variables
+	var t0 = time.Date(2021, 8, 28, 9, 10, 32, 0, time.UTC)
+	defer setPeers(0, 0)
+	setPeers(0, 0)
+
+	// test older line does not overwrite newer peers, ie held lines
+	updatePeers(12, 16, t0)
+	if err := updatePeers(16, 16, t0.Add(-time.Minute)); err != nil {
+		t.Fatalf("data.updatePeers() returned error: %v", err)
+	}
+	if np, sp := getPeers(); np != 12 || sp != 16 {
+		t.Fatalf("data.getPeers() returned: %v, %v, wanted: %v, %v", np, sp, 12, 16)
+	}
+
+	// test newer line applied
+	updatePeers(14, 16, t0.Add(time.Second))
+	if np, _ := getPeers(); np != 14 {
+		t.Fatalf("data.getPeers() returned: %v, wanted: %v", np, 14)
+	}
+}
+
+func TestNodeInfo(t *testing.T) {
+	// sim bootstrap address and peers
+	setAddr("0x8d25fa2e7d")
+	setPeers(8, 16)
+	defer setAddr("")
+
+	addr, np, sp := NodeInfo()
+	if addr != "0x8d25fa2e7d" || np != 8 || sp != 16 {
+		t.Fatalf("data.NodeInfo() returned: %v, %v, %v", addr, np, sp)
+	}
+}
+
+func BenchmarkP2PNumPeersParse(b *testing.B) {
+	// sim bootstrap address
+	setAddr("0x8d25fa2e7d")
+
+	p2p := NewP2PNumPeers()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(P2PNumPeersEx)))
+
+	for i := 0; i < b.N; i++ {
+		if err := p2p.Parse(P2PNumPeersEx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
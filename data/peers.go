@@ -0,0 +1,229 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPeerInterval = 5 * time.Minute
+	defaultFlapAt       = 4 // sufficiency changes per window
+	peerSummaryEvent    = "peer_summary"
+	peerFlushTick       = 30 * time.Second
+)
+
+var (
+	peerHist = NewPeerHistory(defaultPeerInterval, defaultFlapAt)
+)
+
+type PeerSummary struct {
+	Addr       string    `json:"address"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Samples    int       `json:"samples"`
+	Min        int       `json:"min"`
+	Avg        float64   `json:"avg"`
+	Max        int       `json:"max"`
+	Sufficient int       `json:"sufficient_peers"`
+	Below      int       `json:"below_sufficient"` // samples under sufficient
+	Flaps      int       `json:"flaps"`
+	Flapping   bool      `json:"flapping"`
+}
+
+type PeerWindows struct {
+	Current *PeerSummary `json:"current,omitempty"`
+	Last    *PeerSummary `json:"last,omitempty"`
+}
+
+type peerWindow struct {
+	sum    PeerSummary
+	total  int
+	lastOK bool
+}
+
+type PeerHistory struct {
+	mu       sync.Mutex
+	interval time.Duration
+	flapAt   int
+	cur      *peerWindow
+	last     *PeerSummary
+	seenAt   time.Time // log time of newest sample
+	seenWall time.Time // wall time newest sample seen at
+}
+
+func NewPeerHistory(interval time.Duration, flapAt int) *PeerHistory {
+	return &PeerHistory{
+		interval: interval,
+		flapAt:   flapAt,
+	}
+}
+
+func PeerHistoryFromEnv() (*PeerHistory, error) {
+	interval := defaultPeerInterval
+	flapAt := defaultFlapAt
+
+	if v := os.Getenv("PEER_SUMMARY_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			s := fmt.Sprintf("invalid PEER_SUMMARY_INTERVAL: %s", v)
+			return nil, errors.New(s)
+		}
+		interval = d
+	}
+
+	if v := os.Getenv("PEER_FLAP_THRESHOLD"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			s := fmt.Sprintf("invalid PEER_FLAP_THRESHOLD: %s", v)
+			return nil, errors.New(s)
+		}
+		flapAt = n
+	}
+
+	return NewPeerHistory(interval, flapAt), nil
+}
+
+func SetPeerHistory(h *PeerHistory) {
+	peerHist = h
+}
+
+func PeerStatus() interface{} {
+	return peerHist.Status()
+}
+
+func (h *PeerHistory) Status() interface{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// current window so far and last closed window
+	st := PeerWindows{Last: h.last}
+	if h.cur != nil {
+		c := h.summary(h.cur)
+		st.Current = &c
+	}
+
+	return st
+}
+
+func (h *PeerHistory) observe(addr string, t time.Time, np, sp int) *PeerSummary {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// late samples, ie held lines or backfill, do not split windows
+	if h.cur != nil && t.Before(h.cur.sum.Start) {
+		return nil
+	}
+	if t.After(h.seenAt) {
+		h.seenAt = t
+		h.seenWall = time.Now()
+	}
+
+	// log time windows, ie replayed logs summarize the same
+	var closed *PeerSummary
+	if h.cur != nil && !t.Before(h.cur.sum.Start.Add(h.interval)) {
+		s := h.summary(h.cur)
+		h.last = &s
+		closed = &s
+		h.cur = nil
+	}
+	if h.cur == nil {
+		h.cur = &peerWindow{sum: PeerSummary{Start: t.Truncate(h.interval)}}
+	}
+
+	w := h.cur
+	ok := np >= sp
+	if w.sum.Samples > 0 && ok != w.lastOK {
+		w.sum.Flaps++
+	}
+	if !ok {
+		w.sum.Below++
+	}
+	if np < w.sum.Min || w.sum.Samples == 0 {
+		w.sum.Min = np
+	}
+	if np > w.sum.Max || w.sum.Samples == 0 {
+		w.sum.Max = np
+	}
+	w.sum.Addr = addr
+	w.sum.Sufficient = sp
+	w.sum.Samples++
+	w.total += np
+	w.lastOK = ok
+
+	return closed
+}
+
+func (h *PeerHistory) flush(now time.Time) *PeerSummary {
+	// close window once log time passes its end, ie no peers lines
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cur == nil || now.Before(h.cur.sum.Start.Add(h.interval)) {
+		return nil
+	}
+
+	s := h.summary(h.cur)
+	h.last = &s
+
+	// empty window follows, samples stay zero while peers not logged
+	h.cur = &peerWindow{sum: PeerSummary{Addr: s.Addr, Sufficient: s.Sufficient, Start: now.Truncate(h.interval)}}
+
+	return &s
+}
+
+func (h *PeerHistory) logNow(wall time.Time) time.Time {
+	// newest log time, advanced by wall time since newest sample
+	h.mu.Lock()
+	seen, seenWall := h.seenAt, h.seenWall
+	h.mu.Unlock()
+
+	now := LastLogTime()
+	if !seen.IsZero() {
+		if t := seen.Add(wall.Sub(seenWall)); t.After(now) {
+			now = t
+		}
+	}
+
+	return now
+}
+
+func RunPeerHistory(ctx context.Context) {
+	// periodic summaries, also when peers lines stop
+	ticker := time.NewTicker(peerFlushTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			h := peerHist
+			if s := h.flush(h.logNow(now)); s != nil {
+				PublishEvent(NewEvent(peerSummaryEvent, s))
+			}
+		}
+	}
+}
+
+func (h *PeerHistory) summary(w *peerWindow) PeerSummary {
+	s := w.sum
+	s.End = s.Start.Add(h.interval)
+	if s.Samples > 0 {
+		s.Avg = float64(w.total) / float64(s.Samples)
+	}
+	s.Flapping = s.Flaps >= h.flapAt
+
+	return s
+}
+
+func observePeers(addr string, t time.Time, np, sp int) {
+	// send summary once a window closes
+	if s := peerHist.observe(addr, t, np, sp); s != nil {
+		PublishEvent(NewEvent(peerSummaryEvent, s))
+	}
+}
@@ -0,0 +1,134 @@
+package data
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestPeerHistoryFromEnv(t *testing.T) {
+	// test defaults
+	os.Unsetenv("PEER_SUMMARY_INTERVAL")
+	os.Unsetenv("PEER_FLAP_THRESHOLD")
+	h, err := PeerHistoryFromEnv()
+	if err != nil || h.interval != defaultPeerInterval || h.flapAt != defaultFlapAt {
+		t.Fatalf("data.PeerHistoryFromEnv() returned: %+v, %v", h, err)
+	}
+
+	// test invalid threshold
+	os.Setenv("PEER_FLAP_THRESHOLD", "0")
+	defer os.Unsetenv("PEER_FLAP_THRESHOLD")
+	if h, err = PeerHistoryFromEnv(); err == nil {
+		t.Fatalf("data.PeerHistoryFromEnv() returned: %+v, wanted error", h)
+	}
+}
+
+func TestPeerHistoryObserve(t *testing.T) {
+	// setup test variables
+	var h = NewPeerHistory(time.Minute, 3)
+	var t0 = time.Date(2021, 8, 28, 9, 0, 0, 0, time.UTC)
+	var peers = []int{16, 12, 16, 10, 16}
+
+	// test samples within window, each sufficiency change a flap
+	for i, np := range peers {
+		if s := h.observe("0x8d25fa2e7d", t0.Add(time.Duration(i)*time.Second), np, 16); s != nil {
+			t.Fatalf("data.PeerHistory.observe() closed window early: %+v", s)
+		}
+	}
+
+	// test next window closes summary
+	s := h.observe("0x8d25fa2e7d", t0.Add(time.Minute), 16, 16)
+	if s == nil {
+		t.Fatalf("data.PeerHistory.observe() returned: nil, wanted summary")
+	}
+
+	want := PeerSummary{
+		Addr:       "0x8d25fa2e7d",
+		Start:      t0,
+		End:        t0.Add(time.Minute),
+		Samples:    5,
+		Min:        10,
+		Avg:        14,
+		Max:        16,
+		Sufficient: 16,
+		Below:      2,
+		Flaps:      4,
+		Flapping:   true,
+	}
+	if *s != want {
+		t.Fatalf("data.PeerHistory.observe() returned: %+v, wanted: %+v", *s, want)
+	}
+
+	// test status shows current and last window
+	st := h.Status().(PeerWindows)
+	if st.Current == nil || st.Current.Samples != 1 || st.Last == nil || st.Last.Flaps != 4 {
+		t.Fatalf("data.PeerHistory.Status() returned: %+v", st)
+	}
+}
+
+func TestPeerHistoryLateSample(t *testing.T) {
+	// setup test variables
+	var h = NewPeerHistory(time.Minute, 3)
+	var t0 = time.Date(2021, 8, 28, 9, 1, 0, 0, time.UTC)
+
+	// test earlier sample, ie held line, does not close window
+	h.observe("0x8d25fa2e7d", t0, 16, 16)
+	if s := h.observe("0x8d25fa2e7d", t0.Add(-30*time.Second), 10, 16); s != nil {
+		t.Fatalf("data.PeerHistory.observe() closed window on late sample: %+v", s)
+	}
+
+	st := h.Status().(PeerWindows)
+	if st.Current == nil || st.Current.Samples != 1 || st.Current.Min != 16 || st.Last != nil {
+		t.Fatalf("data.PeerHistory.Status() returned: %+v", st)
+	}
+}
+
+func TestPeerHistoryFlush(t *testing.T) {
+	// setup test variables
+	var h = NewPeerHistory(time.Minute, 3)
+	var t0 = time.Date(2021, 8, 28, 9, 0, 0, 0, time.UTC)
+
+	h.observe("0x8d25fa2e7d", t0, 12, 16)
+
+	// test window kept open within interval
+	if s := h.flush(t0.Add(30 * time.Second)); s != nil {
+		t.Fatalf("data.PeerHistory.flush() returned: %+v, wanted nil", s)
+	}
+
+	// test window closed once peers lines stop
+	s := h.flush(t0.Add(90 * time.Second))
+	if s == nil || s.Samples != 1 || s.Min != 12 || !s.End.Equal(t0.Add(time.Minute)) {
+		t.Fatalf("data.PeerHistory.flush() returned: %+v", s)
+	}
+
+	// test empty windows summarized while peers not logged
+	s = h.flush(t0.Add(150 * time.Second))
+	if s == nil || s.Samples != 0 || s.Addr != "0x8d25fa2e7d" || !s.Start.Equal(t0.Add(time.Minute)) {
+		t.Fatalf("data.PeerHistory.flush() returned: %+v, wanted empty window", s)
+	}
+
+	// test next sample resets min and max of empty window
+	h.observe("0x8d25fa2e7d", t0.Add(160*time.Second), 14, 16)
+	if st := h.Status().(PeerWindows); st.Current.Min != 14 || st.Current.Max != 14 {
+		t.Fatalf("data.PeerHistory.Status() returned: %+v", st.Current)
+	}
+}
+
+func TestObservePeers(t *testing.T) {
+	// setup test variables
+	var sink = &testSink{}
+	var t0 = time.Date(2021, 8, 28, 9, 0, 0, 0, time.UTC)
+
+	AddSink(sink)
+	defer ResetSinks()
+	SetPeerHistory(NewPeerHistory(time.Minute, defaultFlapAt))
+	defer SetPeerHistory(NewPeerHistory(defaultPeerInterval, defaultFlapAt))
+
+	// test summary published when window closes
+	observePeers("0x8d25fa2e7d", t0, 16, 16)
+	observePeers("0x8d25fa2e7d", t0.Add(2*time.Minute), 16, 16)
+
+	if len(sink.events) != 1 || sink.events[0].Type != peerSummaryEvent {
+		t.Fatalf("data.observePeers() published: %+v", sink.events)
+	}
+}
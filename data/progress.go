@@ -0,0 +1,236 @@
+package data
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultProgressInterval = 5 * time.Minute
+	defaultConsensusStall   = 2 * time.Minute
+	progressRateWindow      = time.Hour
+	consensusProgressEvent  = "consensus_progress"
+)
+
+var (
+	progress = NewConsensusTracker(defaultProgressInterval, defaultConsensusStall)
+)
+
+type ConsensusState struct {
+	Addr            string    `json:"address"`
+	Epoch           int       `json:"epoch"`
+	Round           int       `json:"round"`
+	Height          int       `json:"height"`
+	EpochAt         time.Time `json:"epoch_at"` // log time epoch last advanced
+	RoundAt         time.Time `json:"round_at"`
+	SeenAt          time.Time `json:"seen_at"`
+	RoundsPerHour   float64   `json:"rounds_per_hour"`
+	EpochsPerMinute float64   `json:"epochs_per_minute"`
+	Stalled         bool      `json:"stalled"` // node logging, epoch not advancing
+}
+
+type epochSample struct {
+	t     time.Time
+	epoch int
+}
+
+type ConsensusTracker struct {
+	mu         sync.Mutex
+	interval   time.Duration
+	stallAfter time.Duration
+	st         ConsensusState
+	rounds     []time.Time
+	epochs     []epochSample
+	blockAt    time.Time // newest block sample, older ones ignored
+	sentAt     time.Time // log time of last epoch record sent
+	published  time.Time
+}
+
+func NewConsensusTracker(interval, stallAfter time.Duration) *ConsensusTracker {
+	return &ConsensusTracker{
+		interval:   interval,
+		stallAfter: stallAfter,
+	}
+}
+
+func ConsensusTrackerFromEnv() (*ConsensusTracker, error) {
+	interval, stall := defaultProgressInterval, defaultConsensusStall
+
+	for _, d := range []struct {
+		name string
+		v    *time.Duration
+	}{
+		{"CONSENSUS_SUMMARY_INTERVAL", &interval},
+		{"CONSENSUS_STALL_AFTER", &stall},
+	} {
+		if v := os.Getenv(d.name); v != "" {
+			p, err := time.ParseDuration(v)
+			if err != nil || p <= 0 {
+				s := fmt.Sprintf("invalid %s: %s", d.name, v)
+				return nil, errors.New(s)
+			}
+			*d.v = p
+		}
+	}
+
+	return NewConsensusTracker(interval, stall), nil
+}
+
+func SetConsensusTracker(c *ConsensusTracker) {
+	progress = c
+}
+
+func ConsensusStatus() interface{} {
+	return progress.Status()
+}
+
+func (c *ConsensusTracker) Status() interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.st
+}
+
+func (c *ConsensusTracker) observeBlock(addr string, t time.Time, height, epoch int) *ConsensusState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t.Before(c.blockAt) {
+		return nil
+	}
+
+	return c.block(addr, t, height, epoch)
+}
+
+func (c *ConsensusTracker) sampleBlock(addr string, t time.Time, height, epoch int) (*ConsensusState, bool) {
+	// epoch records sent once per interval of log time, older samples never
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t.Before(c.blockAt) {
+		return nil, false
+	}
+	s := c.block(addr, t, height, epoch)
+
+	if !c.sentAt.IsZero() && t.Sub(c.sentAt) < csSampleEvery {
+		return s, false
+	}
+	c.sentAt = t
+
+	return s, true
+}
+
+func (c *ConsensusTracker) block(addr string, t time.Time, height, epoch int) *ConsensusState {
+	// caller holds lock and skips older samples
+	c.blockAt = t
+	if epoch != c.st.Epoch {
+		c.st.EpochAt = t
+		c.epochs = append(c.epochs, epochSample{t, epoch})
+	}
+	c.st.Epoch = epoch
+	c.st.Height = height
+
+	return c.update(addr, t)
+}
+
+func (c *ConsensusTracker) observeRound(addr string, t time.Time, round int) *ConsensusState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t.Before(c.st.RoundAt) {
+		return nil
+	}
+	c.st.Round = round
+	c.st.RoundAt = t
+	c.rounds = append(c.rounds, t)
+
+	return c.update(addr, t)
+}
+
+func (c *ConsensusTracker) update(addr string, t time.Time) *ConsensusState {
+	// caller holds lock, rates over last hour of log time
+	c.st.Addr = addr
+	if t.After(c.st.SeenAt) {
+		c.st.SeenAt = t
+	}
+	cutoff := c.st.SeenAt.Add(-progressRateWindow)
+
+	for len(c.rounds) > 0 && c.rounds[0].Before(cutoff) {
+		c.rounds = c.rounds[1:]
+	}
+	c.st.RoundsPerHour = 0
+	if n := len(c.rounds); n > 1 {
+		if span := c.rounds[n-1].Sub(c.rounds[0]); span > 0 {
+			c.st.RoundsPerHour = float64(n-1) / span.Hours()
+		}
+	}
+
+	for len(c.epochs) > 0 && c.epochs[0].t.Before(cutoff) {
+		c.epochs = c.epochs[1:]
+	}
+	c.st.EpochsPerMinute = 0
+	if n := len(c.epochs); n > 1 {
+		if span := c.epochs[n-1].t.Sub(c.epochs[0].t); span > 0 {
+			c.st.EpochsPerMinute = float64(c.epochs[n-1].epoch-c.epochs[0].epoch) / span.Minutes()
+		}
+	}
+
+	c.st.Stalled = !c.st.EpochAt.IsZero() && c.st.SeenAt.Sub(c.st.EpochAt) > c.stallAfter
+
+	// publish once per interval of log time
+	if t.Sub(c.published) < c.interval && !t.Before(c.published) {
+		return nil
+	}
+	c.published = t
+	s := c.st
+
+	return &s
+}
+
+func observeProgress(b []byte, kind int) {
+	// received block and new round lines, not sent as records
+	addr := getAddr()
+	if addr == "" {
+		return
+	}
+
+	var vh, ve, vr int
+	for k, v, rest := nextKeyValue(b); k != nil; k, v, rest = nextKeyValue(rest) {
+		n, err := atoi(v)
+		if err != nil {
+			continue
+		}
+		switch true {
+		case bytes.Equal(k, keyHeightLower):
+			vh = n
+		case bytes.Equal(k, keyEpoch):
+			ve = n
+		case bytes.Equal(k, keyRound):
+			vr = n
+		}
+	}
+
+	t, err := parseTime(b)
+	if err != nil {
+		return
+	}
+
+	var s *ConsensusState
+	switch kind {
+	case umReceivedBlockFilter:
+		if ve == 0 {
+			return
+		}
+		s = progress.observeBlock(addr, t, vh, ve)
+	case umNewRoundFilter:
+		s = progress.observeRound(addr, t, vr)
+	}
+
+	if s != nil {
+		PublishEvent(NewEvent(consensusProgressEvent, s))
+	}
+}
@@ -0,0 +1,95 @@
+package data
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestConsensusTrackerFromEnv(t *testing.T) {
+	// test configured stall
+	os.Setenv("CONSENSUS_STALL_AFTER", "90s")
+	defer os.Unsetenv("CONSENSUS_STALL_AFTER")
+	c, err := ConsensusTrackerFromEnv()
+	if err != nil || c.stallAfter != 90*time.Second || c.interval != defaultProgressInterval {
+		t.Fatalf("data.ConsensusTrackerFromEnv() returned: %+v, %v", c, err)
+	}
+
+	// test invalid interval
+	os.Setenv("CONSENSUS_SUMMARY_INTERVAL", "soon")
+	defer os.Unsetenv("CONSENSUS_SUMMARY_INTERVAL")
+	if c, err = ConsensusTrackerFromEnv(); err == nil {
+		t.Fatalf("data.ConsensusTrackerFromEnv() returned: %+v, wanted error", c)
+	}
+}
+
+func TestConsensusTrackerRates(t *testing.T) {
+	// setup test variables
+	var c = NewConsensusTracker(time.Hour, 2*time.Minute)
+	var t0 = time.Date(2021, 8, 28, 9, 0, 0, 0, time.UTC)
+
+	// test first observation published
+	if s := c.observeRound("0x8d25fa2e7d", t0, 1); s == nil {
+		t.Fatalf("data.ConsensusTracker.observeRound() returned: nil, wanted state")
+	}
+
+	// test round every 6 minutes, epoch 10 per minute
+	for i := 1; i <= 9; i++ {
+		ti := t0.Add(time.Duration(i) * 6 * time.Minute)
+		if s := c.observeRound("0x8d25fa2e7d", ti, i+1); s != nil {
+			t.Fatalf("data.ConsensusTracker.observeRound() published within interval: %+v", s)
+		}
+	}
+	c.observeBlock("0x8d25fa2e7d", t0, 100, 1000)
+	c.observeBlock("0x8d25fa2e7d", t0.Add(time.Minute), 110, 1010)
+
+	st := c.Status().(ConsensusState)
+	if st.Round != 10 || st.Epoch != 1010 || st.Height != 110 || st.RoundsPerHour != 10 || st.EpochsPerMinute != 10 {
+		t.Fatalf("data.ConsensusTracker.Status() returned: %+v", st)
+	}
+
+	// test stalled, rounds logged while epoch stuck
+	if !st.Stalled {
+		t.Fatalf("data.ConsensusTracker.Status() returned stalled: false, wanted: true")
+	}
+	c.observeBlock("0x8d25fa2e7d", t0.Add(time.Hour), 120, 1020)
+	if st = c.Status().(ConsensusState); st.Stalled {
+		t.Fatalf("data.ConsensusTracker.Status() returned stalled: true, wanted: false")
+	}
+
+	// test older samples ignored
+	c.observeBlock("0x8d25fa2e7d", t0.Add(time.Minute), 110, 1010)
+	c.observeRound("0x8d25fa2e7d", t0, 1)
+	if st = c.Status().(ConsensusState); st.Epoch != 1020 || st.Height != 120 || st.Round == 1 {
+		t.Fatalf("data.ConsensusTracker.Status() returned: %+v", st)
+	}
+}
+
+func TestObserveProgress(t *testing.T) {
+	// setup test variables
+	var sink = &testSink{}
+
+	AddSink(sink)
+	defer ResetSinks()
+	SetConsensusTracker(NewConsensusTracker(time.Nanosecond, defaultConsensusStall))
+	defer SetConsensusTracker(NewConsensusTracker(defaultProgressInterval, defaultConsensusStall))
+	setAddr("0x8d25fa2e7d")
+	defer ResetNode()
+
+	// test received block and new round lines tracked, not sent
+	if err := NewUMBroadcast().Parse(UMReceivedEx); err != errNoMatch {
+		t.Fatalf("data.UMBroadcastParse() returned: %v, wanted error: %v", err, errNoMatch)
+	}
+	if err := NewUMBroadcast().Parse(UMNewRoundEx); err != errNoMatch {
+		t.Fatalf("data.UMBroadcastParse() returned: %v, wanted error: %v", err, errNoMatch)
+	}
+
+	st := ConsensusStatus().(ConsensusState)
+	if st.Epoch != 11841048 || st.Height != 11759201 || st.Round != 7 || st.Addr != "0x8d25fa2e7d" {
+		t.Fatalf("data.ConsensusStatus() returned: %+v", st)
+	}
+
+	if len(sink.events) != 2 || sink.events[0].Type != consensusProgressEvent {
+		t.Fatalf("data.observeProgress() published: %+v", sink.events)
+	}
+}
@@ -0,0 +1,236 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRewardWindow = 24 * time.Hour
+	defaultBlockSeconds = 6.0
+	secondsPerDay       = 24 * 60 * 60
+)
+
+var (
+	errNoSchedule = errors.New("no reward schedule")
+	rewards       = NewRewardEstimator("")
+)
+
+type RewardPeriod struct {
+	FromHeight    int     `json:"from_height"`
+	VoteEvery     int     `json:"vote_every_blocks"` // expected blocks between votes
+	RewardPerVote float64 `json:"reward_per_vote"`
+}
+
+type RewardSchedule struct {
+	Token        string         `json:"token"`
+	BlockSeconds float64        `json:"block_seconds"` // used until votes give block time
+	Periods      []RewardPeriod `json:"periods"`
+}
+
+type RewardEstimate struct {
+	Addr           string  `json:"address"`
+	Token          string  `json:"token,omitempty"`
+	Votes          int     `json:"votes"`
+	Expected       int     `json:"expected_votes"`
+	Participation  float64 `json:"participation"`
+	FromHeight     int     `json:"from_height"`
+	ToHeight       int     `json:"to_height"`
+	BlockSeconds   float64 `json:"block_seconds"`
+	VotesPerDay    float64 `json:"votes_per_day"`
+	RewardsPerDay  float64 `json:"rewards_per_day"`
+	RewardsPerWeek float64 `json:"rewards_per_week"`
+	ScheduleError  string  `json:"schedule_error,omitempty"`
+}
+
+type voteSample struct {
+	height int
+	ts     int // vote creation unix time
+}
+
+type RewardEstimator struct {
+	mu      sync.Mutex
+	fp      string
+	modTime time.Time
+	size    int64
+	sched   *RewardSchedule
+	err     error
+	window  time.Duration
+	votes   map[int]int // height to vote unix time
+}
+
+func NewRewardEstimator(fp string) *RewardEstimator {
+	return &RewardEstimator{
+		fp:     fp,
+		window: defaultRewardWindow,
+		votes:  make(map[int]int),
+	}
+}
+
+func RewardEstimatorFromEnv() (*RewardEstimator, error) {
+	// schedule file optional, participation needs it
+	e := NewRewardEstimator(os.Getenv("REWARD_SCHEDULE_FILE"))
+	if e.fp != "" {
+		if err := e.reload(); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+func SetRewardEstimator(e *RewardEstimator) {
+	rewards = e
+}
+
+func RewardStatus() interface{} {
+	return rewards.Estimate()
+}
+
+func LoadRewardSchedule(fp string) (*RewardSchedule, error) {
+	b, err := os.ReadFile(fp)
+	if err != nil {
+		return nil, err
+	}
+
+	var rs RewardSchedule
+	if err := json.Unmarshal(b, &rs); err != nil {
+		s := fmt.Sprintf("invalid reward schedule %s: %v", fp, err)
+		return nil, errors.New(s)
+	}
+	if len(rs.Periods) == 0 {
+		s := fmt.Sprintf("invalid reward schedule %s: no periods", fp)
+		return nil, errors.New(s)
+	}
+	for _, p := range rs.Periods {
+		if p.VoteEvery <= 0 || p.RewardPerVote < 0 {
+			s := fmt.Sprintf("invalid reward schedule %s: period from height %d", fp, p.FromHeight)
+			return nil, errors.New(s)
+		}
+	}
+	if rs.BlockSeconds <= 0 {
+		rs.BlockSeconds = defaultBlockSeconds
+	}
+
+	// periods by height, latest applies
+	sort.Slice(rs.Periods, func(i, j int) bool { return rs.Periods[i].FromHeight < rs.Periods[j].FromHeight })
+
+	return &rs, nil
+}
+
+func (rs *RewardSchedule) period(height int) RewardPeriod {
+	p := rs.Periods[0]
+	for _, np := range rs.Periods {
+		if np.FromHeight > height {
+			break
+		}
+		p = np
+	}
+	return p
+}
+
+func (e *RewardEstimator) observe(height, ts int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// same vote logged as new block and broadcast
+	e.votes[height] = ts
+
+	// forget votes older than window of newest vote
+	var newest int
+	for _, t := range e.votes {
+		if t > newest {
+			newest = t
+		}
+	}
+	cutoff := newest - int(e.window.Seconds())
+	for h, t := range e.votes {
+		if t < cutoff {
+			delete(e.votes, h)
+		}
+	}
+}
+
+func (e *RewardEstimator) Estimate() RewardEstimate {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	est := RewardEstimate{Addr: getAddr(), Votes: len(e.votes), BlockSeconds: defaultBlockSeconds}
+	if e.fp != "" {
+		if err := e.reload(); err != nil {
+			e.err = err
+		}
+	}
+
+	var samples []voteSample
+	for h, t := range e.votes {
+		samples = append(samples, voteSample{h, t})
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].height < samples[j].height })
+
+	if e.sched != nil {
+		est.Token = e.sched.Token
+		est.BlockSeconds = e.sched.BlockSeconds
+	}
+	switch true {
+	case e.err != nil:
+		est.ScheduleError = e.err.Error()
+	case e.sched == nil:
+		est.ScheduleError = errNoSchedule.Error()
+	}
+	if len(samples) == 0 {
+		return est
+	}
+
+	first, last := samples[0], samples[len(samples)-1]
+	est.FromHeight, est.ToHeight = first.height, last.height
+
+	// block time observed from votes, else schedule
+	if dh, dt := last.height-first.height, last.ts-first.ts; dh > 0 && dt > 0 {
+		est.BlockSeconds = float64(dt) / float64(dh)
+	}
+
+	if e.sched == nil {
+		if dt := last.ts - first.ts; dt > 0 {
+			est.VotesPerDay = float64(len(samples)-1) * secondsPerDay / float64(dt)
+		}
+		return est
+	}
+
+	p := e.sched.period(last.height)
+	est.Expected = (last.height-first.height)/p.VoteEvery + 1
+	est.Participation = float64(est.Votes) / float64(est.Expected)
+	if est.Participation > 1 {
+		est.Participation = 1
+	}
+	est.VotesPerDay = secondsPerDay / est.BlockSeconds / float64(p.VoteEvery) * est.Participation
+	est.RewardsPerDay = est.VotesPerDay * p.RewardPerVote
+	est.RewardsPerWeek = est.RewardsPerDay * 7
+
+	return est
+}
+
+func (e *RewardEstimator) reload() error {
+	// caller holds lock or owns estimator, reload when file changed
+	info, err := os.Stat(e.fp)
+	if err != nil {
+		return err
+	}
+	if e.sched != nil && info.ModTime().Equal(e.modTime) && info.Size() == e.size {
+		return nil
+	}
+
+	rs, err := LoadRewardSchedule(e.fp)
+	if err != nil {
+		return err
+	}
+	e.sched, e.err = rs, nil
+	e.modTime, e.size = info.ModTime(), info.Size()
+
+	return nil
+}
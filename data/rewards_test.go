@@ -0,0 +1,103 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeSchedule(t *testing.T, fp, s string) {
+	if err := os.WriteFile(fp, []byte(s), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadRewardSchedule(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "rewards.json")
+
+	// test periods sorted, block time defaulted
+	writeSchedule(t, fp, `{"token":"TFUEL","periods":[{"from_height":200,"vote_every_blocks":50,"reward_per_vote":2},{"from_height":0,"vote_every_blocks":100,"reward_per_vote":1}]}`)
+	rs, err := LoadRewardSchedule(fp)
+	if err != nil {
+		t.Fatalf("data.LoadRewardSchedule() returned error: %v", err)
+	}
+	if rs.BlockSeconds != defaultBlockSeconds || rs.period(100).VoteEvery != 100 || rs.period(250).VoteEvery != 50 {
+		t.Fatalf("data.LoadRewardSchedule() returned: %+v", rs)
+	}
+
+	// test invalid period
+	writeSchedule(t, fp, `{"periods":[{"from_height":0,"vote_every_blocks":0}]}`)
+	if rs, err = LoadRewardSchedule(fp); err == nil {
+		t.Fatalf("data.LoadRewardSchedule() returned: %+v, wanted error", rs)
+	}
+
+	// test no periods
+	writeSchedule(t, fp, `{"token":"TFUEL"}`)
+	if rs, err = LoadRewardSchedule(fp); err == nil {
+		t.Fatalf("data.LoadRewardSchedule() returned: %+v, wanted error", rs)
+	}
+}
+
+func TestRewardEstimate(t *testing.T) {
+	// setup test variables
+	fp := filepath.Join(t.TempDir(), "rewards.json")
+	writeSchedule(t, fp, `{"token":"TFUEL","periods":[{"from_height":0,"vote_every_blocks":100,"reward_per_vote":0.5}]}`)
+
+	os.Setenv("REWARD_SCHEDULE_FILE", fp)
+	defer os.Unsetenv("REWARD_SCHEDULE_FILE")
+	e, err := RewardEstimatorFromEnv()
+	if err != nil {
+		t.Fatalf("data.RewardEstimatorFromEnv() returned error: %v", err)
+	}
+
+	// test 4 of 5 votes, block every 6s
+	for _, h := range []int{1000, 1100, 1300, 1400} {
+		e.observe(h, 1630155386+(h-1000)*6)
+	}
+	e.observe(1100, 1630155386+600) // same vote logged twice
+
+	est := e.Estimate()
+	if est.Votes != 4 || est.Expected != 5 || est.Participation != 0.8 || est.BlockSeconds != 6 || est.Token != "TFUEL" {
+		t.Fatalf("data.RewardEstimator.Estimate() returned: %+v", est)
+	}
+
+	// 144 votes per day at full participation
+	if est.VotesPerDay != 115.2 || est.RewardsPerDay != 57.6 || est.RewardsPerWeek != 57.6*7 {
+		t.Fatalf("data.RewardEstimator.Estimate() returned: %+v", est)
+	}
+
+	// test schedule reloaded when file changes
+	writeSchedule(t, fp, `{"token":"TFUEL","periods":[{"from_height":0,"vote_every_blocks":100,"reward_per_vote":1.0}]}`)
+	os.Chtimes(fp, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+	if est = e.Estimate(); est.RewardsPerDay != 115.2 {
+		t.Fatalf("data.RewardEstimator.Estimate() returned: %+v, wanted reload", est)
+	}
+
+	// test broken schedule keeps last good one
+	writeSchedule(t, fp, `{`)
+	os.Chtimes(fp, time.Now().Add(2*time.Minute), time.Now().Add(2*time.Minute))
+	if est = e.Estimate(); est.ScheduleError == "" || est.RewardsPerDay != 115.2 {
+		t.Fatalf("data.RewardEstimator.Estimate() returned: %+v, wanted schedule error", est)
+	}
+}
+
+func TestRewardEstimateNoSchedule(t *testing.T) {
+	e := NewRewardEstimator("")
+
+	// test votes per day from vote times only
+	e.observe(1000, 0)
+	e.observe(1100, 43200)
+	e.observe(1200, 86400)
+
+	est := e.Estimate()
+	if est.ScheduleError != errNoSchedule.Error() || est.VotesPerDay != 2 || est.RewardsPerDay != 0 {
+		t.Fatalf("data.RewardEstimator.Estimate() returned: %+v", est)
+	}
+
+	// test votes outside window forgotten
+	e.observe(2000, 86400*3)
+	if est = e.Estimate(); est.Votes != 1 {
+		t.Fatalf("data.RewardEstimator.Estimate() returned votes: %v, wanted: %v", est.Votes, 1)
+	}
+}
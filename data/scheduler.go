@@ -0,0 +1,344 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSendRate   = 2.0 // requests per second
+	defaultSendBurst  = 10
+	defaultSendJitter = 6000 * time.Millisecond
+	defaultQueueSize  = 1024
+	maxSendAttempts   = 5
+	baseSendBackoff   = time.Second
+	maxSendBackoff    = time.Minute
+)
+
+var (
+	scheduler     *Scheduler // nil sends inline
+	errQueueFull  = errors.New("send queue full")
+	errBadSetting = errors.New("invalid scheduler setting")
+)
+
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+type queued struct {
+	job      Job
+	due      time.Time // enqueue time plus jitter, or retry backoff
+	attempts int
+}
+
+type Scheduler struct {
+	rate    float64 // tokens per second, zero is unlimited
+	burst   float64
+	jitter  time.Duration
+	clock   Clock
+	mu      sync.Mutex // guards rand and pending
+	run     sync.Mutex // guards bucket, ie one of run or flush
+	rand    *rand.Rand
+	send    func(Job) error
+	queue   chan queued
+	pending []queued // received jobs waiting until due
+	tokens  float64
+	last    time.Time
+}
+
+func NewScheduler(rate float64, burst int, jitter time.Duration) *Scheduler {
+	return &Scheduler{
+		rate:   rate,
+		burst:  float64(burst),
+		jitter: jitter,
+		clock:  realClock{},
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		send:   deliver,
+		queue:  make(chan queued, defaultQueueSize),
+		tokens: float64(burst),
+	}
+}
+
+func SchedulerFromEnv() (*Scheduler, error) {
+	rate := defaultSendRate
+	burst := defaultSendBurst
+	jitter := defaultSendJitter
+
+	if v := os.Getenv("SEND_RATE"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 {
+			return nil, errBadSetting
+		}
+		rate = f
+	}
+
+	if v := os.Getenv("SEND_BURST"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, errBadSetting
+		}
+		burst = n
+	}
+
+	if v := os.Getenv("SEND_JITTER"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, errBadSetting
+		}
+		jitter = d
+	}
+
+	return NewScheduler(rate, burst, jitter), nil
+}
+
+func SetScheduler(s *Scheduler) {
+	scheduler = s
+}
+
+func QueueLen() int {
+	if scheduler == nil {
+		return 0
+	}
+	return scheduler.Len()
+}
+
+func (s *Scheduler) Submit(j Job) error {
+	// never block the log reader
+	q := queued{job: j, due: s.clock.Now().Add(s.delay())}
+	select {
+	case s.queue <- q:
+		return nil
+	default:
+		return errQueueFull
+	}
+}
+
+func (s *Scheduler) SubmitWait(ctx context.Context, j Job) error {
+	// block until queued, ie backpressure to the pipeline
+	q := queued{job: j, due: s.clock.Now().Add(s.delay())}
+	select {
+	case s.queue <- q:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.queue) + len(s.pending)
+}
+
+func (s *Scheduler) Run(ctx context.Context) {
+	s.run.Lock()
+	defer s.run.Unlock()
+
+	for {
+		// send due jobs, only waiting on tokens
+		if q, ok := s.popDue(); ok {
+			if err := s.take(ctx); err != nil {
+				s.pushFront(q)
+				return
+			}
+			s.attempt(q)
+			continue
+		}
+
+		// wait for next job due or received, keep pending bounded
+		var wake <-chan time.Time
+		if due, ok := s.nextDue(); ok {
+			wake = s.clock.After(due.Sub(s.clock.Now()))
+		}
+		queue := s.queue
+		if s.pendingLen() >= cap(s.queue) {
+			queue = nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case q := <-queue:
+			s.push(q)
+		case <-wake:
+		}
+	}
+}
+
+func (s *Scheduler) Flush(ctx context.Context) error {
+	// send remaining jobs without jitter, ie on shutdown
+	s.run.Lock()
+	defer s.run.Unlock()
+
+	for {
+		q, ok := s.popFront()
+		if !ok {
+			select {
+			case q = <-s.queue:
+			default:
+				return nil
+			}
+		}
+		if err := s.take(ctx); err != nil {
+			s.pushFront(q)
+			return err
+		}
+		if err := s.send(q.job); err != nil {
+			fmt.Println("error sending:", err) // perhaps log to log file
+		}
+	}
+}
+
+func (s *Scheduler) attempt(q queued) {
+	err := s.send(q.job)
+	if err == nil {
+		return
+	}
+	fmt.Println("error sending:", err) // perhaps log to log file
+
+	// retry with backoff unless out of attempts or key taken meanwhile
+	q.attempts++
+	if q.attempts >= maxSendAttempts {
+		return
+	}
+	if q.job.Key != "" && !dedup.reserve(q.job.Key) {
+		return
+	}
+	q.due = s.clock.Now().Add(backoff(q.attempts))
+	s.push(q)
+}
+
+func backoff(attempts int) time.Duration {
+	d := baseSendBackoff
+	for i := 1; i < attempts && d < maxSendBackoff; i++ {
+		d *= 2
+	}
+	if d > maxSendBackoff {
+		d = maxSendBackoff
+	}
+	return d
+}
+
+func (s *Scheduler) push(q queued) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending = append(s.pending, q)
+}
+
+func (s *Scheduler) pushFront(q queued) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending = append([]queued{q}, s.pending...)
+}
+
+func (s *Scheduler) popFront() (queued, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.pending) == 0 {
+		return queued{}, false
+	}
+	q := s.pending[0]
+	s.pending = s.pending[1:]
+	return q, true
+}
+
+func (s *Scheduler) popDue() (queued, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// earliest due job, ties keep arrival order
+	now := s.clock.Now()
+	i := -1
+	for j, q := range s.pending {
+		if q.due.After(now) {
+			continue
+		}
+		if i < 0 || q.due.Before(s.pending[i].due) {
+			i = j
+		}
+	}
+	if i < 0 {
+		return queued{}, false
+	}
+	q := s.pending[i]
+	s.pending = append(s.pending[:i], s.pending[i+1:]...)
+	return q, true
+}
+
+func (s *Scheduler) nextDue() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due time.Time
+	for i, q := range s.pending {
+		if i == 0 || q.due.Before(due) {
+			due = q.due
+		}
+	}
+	return due, len(s.pending) > 0
+}
+
+func (s *Scheduler) pendingLen() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.pending)
+}
+
+func (s *Scheduler) delay() time.Duration {
+	// spread requests of many clients, ie thundering herd
+	if s.jitter <= 0 {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Duration(s.rand.Int63n(int64(s.jitter)))
+}
+
+func (s *Scheduler) take(ctx context.Context) error {
+	if s.rate <= 0 {
+		return nil
+	}
+
+	for {
+		// refill bucket for time elapsed since last take
+		now := s.clock.Now()
+		if !s.last.IsZero() {
+			s.tokens += now.Sub(s.last).Seconds() * s.rate
+			if s.tokens > s.burst {
+				s.tokens = s.burst
+			}
+		}
+		s.last = now
+
+		if s.tokens >= 1 {
+			s.tokens--
+			return nil
+		}
+
+		// wait for next token
+		d := time.Duration((1 - s.tokens) / s.rate * float64(time.Second))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.clock.After(d):
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package data
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock advances its own time whenever a caller waits on it.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// stopClock never fires, ie waits only end on cancel.
+type stopClock struct {
+	now time.Time
+}
+
+func (c stopClock) Now() time.Time                         { return c.now }
+func (c stopClock) After(d time.Duration) <-chan time.Time { return nil }
+
+func newTestScheduler(rate float64, burst int, jitter time.Duration) (*Scheduler, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2021, 8, 28, 9, 0, 0, 0, time.UTC)}
+	s := NewScheduler(rate, burst, jitter)
+	s.clock = clock
+	s.rand = rand.New(rand.NewSource(1))
+	return s, clock
+}
+
+func TestSchedulerRateLimit(t *testing.T) {
+	// setup test variables
+	var sent []time.Time
+	var done = make(chan struct{})
+
+	s, clock := newTestScheduler(1, 2, 0)
+	s.send = func(j Job) error {
+		sent = append(sent, clock.Now())
+		if len(sent) == 4 {
+			close(done)
+		}
+		return nil
+	}
+
+	for i := 0; i < 4; i++ {
+		if err := s.Submit(Job{}); err != nil {
+			t.Fatalf("data.Scheduler.Submit() returned error: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+	<-done
+
+	// test burst sent at once then one per second
+	start := sent[0]
+	want := []time.Duration{0, 0, time.Second, 2 * time.Second}
+	for i, d := range want {
+		if got := sent[i].Sub(start); got != d {
+			t.Fatalf("data.Scheduler.Run() sent job %d at: %v, wanted: %v", i, got, d)
+		}
+	}
+}
+
+func TestSchedulerJitter(t *testing.T) {
+	// setup test variables
+	var jitter = 6 * time.Second
+
+	s, clock := newTestScheduler(0, 1, jitter)
+	start := clock.Now()
+
+	// test delay bounded by jitter and deterministic with seeded source
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		got := s.delay()
+		want := time.Duration(r.Int63n(int64(jitter)))
+		if got != want || got < 0 || got >= jitter {
+			t.Fatalf("data.Scheduler.delay() returned: %v, wanted: %v", got, want)
+		}
+	}
+
+	// test jobs held until due without blocking jobs due earlier
+	var sent []queued
+	var done = make(chan struct{})
+	s.send = func(j Job) error {
+		sent = append(sent, queued{job: j, due: clock.Now()})
+		if len(sent) == 2 {
+			close(done)
+		}
+		return nil
+	}
+	s.push(queued{job: Job{URL: "late"}, due: start.Add(5 * time.Second)})
+	s.push(queued{job: Job{URL: "early"}, due: start.Add(time.Second)})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+	<-done
+
+	if sent[0].job.URL != "early" || sent[1].job.URL != "late" {
+		t.Fatalf("data.Scheduler.Run() sent: %v, %v, wanted: %v, %v", sent[0].job.URL, sent[1].job.URL, "early", "late")
+	}
+	if !sent[1].due.Equal(start.Add(5 * time.Second)) {
+		t.Fatalf("data.Scheduler.Run() sent job at: %v, wanted: %v", sent[1].due, start.Add(5*time.Second))
+	}
+}
+
+func TestSchedulerRetry(t *testing.T) {
+	// setup test variables
+	var sent []time.Time
+	var done = make(chan struct{})
+
+	s, clock := newTestScheduler(0, 1, 0)
+	start := clock.Now()
+	s.send = func(j Job) error {
+		sent = append(sent, clock.Now())
+		if len(sent) < 3 {
+			return errQueueFull
+		}
+		close(done)
+		return nil
+	}
+	s.Submit(Job{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+	<-done
+
+	// test failed sends retried with growing backoff
+	want := []time.Duration{0, time.Second, 3 * time.Second}
+	for i, d := range want {
+		if got := sent[i].Sub(start); got != d {
+			t.Fatalf("data.Scheduler.Run() sent attempt %d at: %v, wanted: %v", i, got, d)
+		}
+	}
+
+	// test backoff capped
+	if got := backoff(20); got != maxSendBackoff {
+		t.Fatalf("data.backoff() returned: %v, wanted: %v", got, maxSendBackoff)
+	}
+}
+
+func TestSchedulerCancelRequeue(t *testing.T) {
+	s, _ := newTestScheduler(0, 1, 0)
+	s.push(queued{job: Job{URL: "second"}})
+
+	// test job interrupted by cancel while waiting on a token goes back to the head
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.clock = stopClock{now: s.clock.Now()}
+	s.rate = 1
+	s.tokens = 0
+	s.last = s.clock.Now()
+	s.pushFront(queued{job: Job{URL: "first"}})
+	s.Run(ctx)
+
+	var got []string
+	s.rate = 0
+	s.send = func(j Job) error {
+		got = append(got, j.URL)
+		return nil
+	}
+	if err := s.Flush(context.Background()); err != nil {
+		t.Fatalf("data.Scheduler.Flush() returned error: %v", err)
+	}
+	if len(got) != 2 || got[0] != "first" || got[1] != "second" {
+		t.Fatalf("data.Scheduler.Flush() sent: %v, wanted: %v", got, []string{"first", "second"})
+	}
+}
+
+func TestSchedulerSubmitFull(t *testing.T) {
+	s, _ := newTestScheduler(0, 1, 0)
+	s.queue = make(chan queued, 1)
+
+	// test submit never blocks once queue full
+	if err := s.Submit(Job{}); err != nil {
+		t.Fatalf("data.Scheduler.Submit() returned error: %v", err)
+	}
+
+	if err := s.Submit(Job{}); err != errQueueFull {
+		t.Fatalf("data.Scheduler.Submit() returned: %v, wanted: %v", err, errQueueFull)
+	}
+
+	if got := s.Len(); got != 1 {
+		t.Fatalf("data.Scheduler.Len() returned: %v, wanted: %v", got, 1)
+	}
+}
+
+func TestSchedulerFlush(t *testing.T) {
+	// setup test variables
+	var sent int
+
+	s, _ := newTestScheduler(0, 1, time.Hour)
+	s.send = func(j Job) error {
+		sent++
+		return nil
+	}
+
+	for i := 0; i < 3; i++ {
+		s.Submit(Job{})
+	}
+
+	// test flush ignores jitter
+	if err := s.Flush(context.Background()); err != nil {
+		t.Fatalf("data.Scheduler.Flush() returned error: %v", err)
+	}
+
+	if sent != 3 || s.Len() != 0 {
+		t.Fatalf("data.Scheduler.Flush() sent: %v, wanted: %v", sent, 3)
+	}
+}
+
+func TestSchedulerFromEnv(t *testing.T) {
+	defer os.Unsetenv("SEND_RATE")
+	defer os.Unsetenv("SEND_JITTER")
+
+	// test defaults
+	s, err := SchedulerFromEnv()
+	if err != nil {
+		t.Fatalf("data.SchedulerFromEnv() returned error: %v", err)
+	}
+
+	if s.rate != defaultSendRate || s.burst != defaultSendBurst || s.jitter != defaultSendJitter {
+		t.Fatalf("data.SchedulerFromEnv() returned: %v, %v, %v", s.rate, s.burst, s.jitter)
+	}
+
+	// test configured values
+	os.Setenv("SEND_RATE", "0.5")
+	os.Setenv("SEND_JITTER", "250ms")
+	s, err = SchedulerFromEnv()
+	if err != nil {
+		t.Fatalf("data.SchedulerFromEnv() returned error: %v", err)
+	}
+
+	if s.rate != 0.5 || s.jitter != 250*time.Millisecond {
+		t.Fatalf("data.SchedulerFromEnv() returned: %v, %v", s.rate, s.jitter)
+	}
+
+	// test invalid value
+	os.Setenv("SEND_JITTER", "soon")
+	if _, err := SchedulerFromEnv(); err == nil {
+		t.Fatalf("data.SchedulerFromEnv() returned: %v, wanted error", err)
+	}
+}
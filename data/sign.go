@@ -0,0 +1,93 @@
+package data
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	keyIDHeader     = "X-Api-Key-Id"
+	timestampHeader = "X-Signature-Timestamp"
+	nonceHeader     = "X-Signature-Nonce"
+	signatureHeader = "X-Signature"
+	signatureScheme = "v1="
+)
+
+var (
+	signRequests    bool
+	errBadSignature = errors.New("invalid request signature")
+	errStaleRequest = errors.New("request timestamp outside window")
+)
+
+func SetRequestSigning(enabled bool) {
+	signRequests = enabled
+}
+
+func signRequest(req *http.Request, body []byte, key string, now time.Time) error {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	ts := strconv.FormatInt(now.Unix(), 10)
+	n := hex.EncodeToString(nonce)
+
+	// identify key without sending it
+	req.Header.Set(keyIDHeader, keyID(key))
+	req.Header.Set(timestampHeader, ts)
+	req.Header.Set(nonceHeader, n)
+	req.Header.Set(signatureHeader, signatureScheme+signature(key, req, ts, n, body))
+
+	return nil
+}
+
+func VerifyRequest(req *http.Request, body []byte, key string, now time.Time, window time.Duration) error {
+	// server side check, nonce replay tracking left to caller
+	ts := req.Header.Get(timestampHeader)
+	n := req.Header.Get(nonceHeader)
+	sig := strings.TrimPrefix(req.Header.Get(signatureHeader), signatureScheme)
+
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || n == "" {
+		return errBadSignature
+	}
+	if d := now.Sub(time.Unix(sec, 0)); d > window || d < -window {
+		return errStaleRequest
+	}
+
+	want := signature(key, req, ts, n, body)
+	if !hmac.Equal([]byte(sig), []byte(want)) {
+		return errBadSignature
+	}
+
+	return nil
+}
+
+func keyID(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:8])
+}
+
+func signature(key string, req *http.Request, ts, nonce string, body []byte) string {
+	// sign method, host, path with query, timestamp, nonce and body hash
+	bh := sha256.Sum256(body)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	line := []string{req.Method, strings.ToLower(host), req.URL.RequestURI(), ts, nonce, ""}
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(strings.Join(line, "\n")))
+	mac.Write([]byte(hex.EncodeToString(bh[:])))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
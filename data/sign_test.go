@@ -0,0 +1,94 @@
+package data
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSignRequest(t *testing.T) {
+	// setup test variables
+	var body = []byte(`{"height":11759001}`)
+	var key = "devkey"
+	var now = time.Unix(1630155386, 0)
+	var window = 5 * time.Minute
+
+	req, _ := http.NewRequest(http.MethodPost, "http://127.0.0.1:8000/stats/uptimes/broadcasts", bytes.NewReader(body))
+	if err := signRequest(req, body, key, now); err != nil {
+		t.Fatalf("data.signRequest() returned error: %v", err)
+	}
+
+	// test key not sent
+	if req.Header.Get("X-Api-Key") != "" || req.Header.Get(keyIDHeader) != keyID(key) {
+		t.Fatalf("data.signRequest() returned headers: %v", req.Header)
+	}
+
+	// test valid signature
+	if err := VerifyRequest(req, body, key, now.Add(time.Minute), window); err != nil {
+		t.Fatalf("data.VerifyRequest() returned error: %v", err)
+	}
+
+	// test tampered body
+	if err := VerifyRequest(req, []byte(`{"height":1}`), key, now, window); err != errBadSignature {
+		t.Fatalf("data.VerifyRequest() returned: %v, wanted: %v", err, errBadSignature)
+	}
+
+	// test wrong key
+	if err := VerifyRequest(req, body, "otherkey", now, window); err != errBadSignature {
+		t.Fatalf("data.VerifyRequest() returned: %v, wanted: %v", err, errBadSignature)
+	}
+
+	// test request sent to other host, path or query
+	for _, u := range []string{
+		"http://10.0.0.1:8000/stats/uptimes/broadcasts",
+		"http://127.0.0.1:8000/stats/uptimes/peers",
+		"http://127.0.0.1:8000/stats/uptimes/broadcasts?node=other",
+	} {
+		moved, _ := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+		moved.Header = req.Header
+		if err := VerifyRequest(moved, body, key, now, window); err != errBadSignature {
+			t.Fatalf("data.VerifyRequest(%v) returned: %v, wanted: %v", u, err, errBadSignature)
+		}
+	}
+
+	// test replay outside window
+	if err := VerifyRequest(req, body, key, now.Add(time.Hour), window); err != errStaleRequest {
+		t.Fatalf("data.VerifyRequest() returned: %v, wanted: %v", err, errStaleRequest)
+	}
+
+	// test nonce unique per request
+	again, _ := http.NewRequest(http.MethodPost, req.URL.String(), bytes.NewReader(body))
+	signRequest(again, body, key, now)
+	if again.Header.Get(nonceHeader) == req.Header.Get(nonceHeader) {
+		t.Fatalf("data.signRequest() reused nonce: %v", req.Header.Get(nonceHeader))
+	}
+}
+
+func TestPostDataSigned(t *testing.T) {
+	// setup test variables
+	var got error
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got = VerifyRequest(r, b, apiKey, time.Now(), time.Minute)
+		if r.Header.Get("X-Api-Key") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	SetRequestSigning(true)
+	defer SetRequestSigning(false)
+
+	// test server verifies signed request
+	if err := postData(Job{URL: srv.URL + "/stats/uptimes/peers?node=0x8d25fa2e7d", Body: []byte(`{}`)}); err != nil {
+		t.Fatalf("data.postData() returned error: %v", err)
+	}
+
+	if got != nil {
+		t.Fatalf("data.VerifyRequest() returned error: %v", got)
+	}
+}
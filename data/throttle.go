@@ -0,0 +1,41 @@
+package data
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	errThrottled = errors.New("throttled")
+)
+
+type Sampler interface {
+	Sample() bool // false holds record back, ie counted into a later one
+}
+
+type throttle struct {
+	mu    sync.Mutex
+	every time.Duration
+	last  map[string]time.Time
+}
+
+func newThrottle(every time.Duration) *throttle {
+	return &throttle{
+		every: every,
+		last:  make(map[string]time.Time),
+	}
+}
+
+func (th *throttle) allow(key string, t time.Time) bool {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+
+	// allow once per interval of log time, ie also during backfill
+	if last, ok := th.last[key]; ok && t.Sub(last) < th.every && !t.Before(last) {
+		return false
+	}
+	th.last[key] = t
+
+	return true
+}
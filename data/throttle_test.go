@@ -0,0 +1,34 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThrottleAllow(t *testing.T) {
+	th := newThrottle(30 * time.Second)
+	t0 := time.Date(2021, 8, 28, 9, 0, 0, 0, time.UTC)
+
+	// test first record per key allowed
+	if !th.allow("a", t0) {
+		t.Fatalf("data.throttle.allow() returned: false, wanted: true")
+	}
+	if !th.allow("b", t0) {
+		t.Fatalf("data.throttle.allow() returned: false, wanted: true")
+	}
+
+	// test record within interval throttled
+	if th.allow("a", t0.Add(10*time.Second)) {
+		t.Fatalf("data.throttle.allow() returned: true, wanted: false")
+	}
+
+	// test record after interval allowed
+	if !th.allow("a", t0.Add(30*time.Second)) {
+		t.Fatalf("data.throttle.allow() returned: false, wanted: true")
+	}
+
+	// test earlier log time allowed, ie rotated file replay
+	if !th.allow("a", t0.Add(-time.Hour)) {
+		t.Fatalf("data.throttle.allow() returned: false, wanted: true")
+	}
+}
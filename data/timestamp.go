@@ -0,0 +1,171 @@
+package data
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	LauncherTimeSource = "launcher"
+	NodeTimeSource     = "node"
+)
+
+const (
+	maxTimeBrackets = 4 // timestamps only seen in leading brackets
+	maxTimeLen      = 40
+	localTimeLayout = "2006-01-02T15:04:05.999999999"
+)
+
+var (
+	timeMu         sync.RWMutex
+	timeSource     = NodeTimeSource
+	launcherTag    = []byte("ThetaEdgeLauncher")
+	errNoTimestamp = errors.New("no timestamp")
+)
+
+type logTimes struct {
+	launcher time.Time // edge launcher timestamp, ie millisecond precision
+	node     time.Time // theta node timestamp, ie second precision
+}
+
+func SetTimeSource(s string) error {
+	timeMu.Lock()
+	defer timeMu.Unlock()
+
+	switch s {
+	case "", NodeTimeSource:
+		timeSource = NodeTimeSource
+	case LauncherTimeSource:
+		timeSource = LauncherTimeSource
+	default:
+		s := fmt.Sprintf("unknown time source: %s", s)
+		return errors.New(s)
+	}
+
+	return nil
+}
+
+func LineTime(b []byte) (time.Time, error) {
+	// log time of a line per configured time source
+	lt, err := parseTimes(b)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, _ := lt.createdAt()
+	return t, nil
+}
+
+func parseTimes(b []byte) (logTimes, error) {
+	var lt logTimes
+	var found int
+	var launcher bool
+
+	// scan leading bracketed fields, ie "[ts] [info] [ThetaEdgeLauncher] [ts]"
+	rest := b
+	for i := 0; i < maxTimeBrackets && found < 2; i++ {
+		o := bytes.IndexByte(rest, '[')
+		if o < 0 {
+			break
+		}
+		c := bytes.IndexByte(rest[o+1:], ']')
+		if c < 0 {
+			break
+		}
+		field := rest[o+1 : o+1+c]
+		rest = rest[o+c+2:]
+
+		if bytes.Equal(field, launcherTag) {
+			launcher = true
+			continue
+		}
+
+		t, err := parseTimestamp(field)
+		if err != nil {
+			continue
+		}
+
+		// timestamp after launcher tag or second timestamp is node time
+		if launcher || found > 0 {
+			lt.node = t
+		} else {
+			lt.launcher = t
+		}
+		found++
+	}
+
+	if found == 0 {
+		return lt, errNoTimestamp
+	}
+
+	return lt, nil
+}
+
+func parseTimestamp(b []byte) (time.Time, error) {
+	var buf [maxTimeLen]byte
+	var n int
+	var sep bool
+
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 || len(b) > maxTimeLen || b[0] < '0' || b[0] > '9' {
+		return time.Time{}, errNoTimestamp
+	}
+
+	// normalize date time separator, ie "2021-08-28  09:10:32" or "2021-08-28T09:10:32"
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		if isSpaceChar(c) {
+			if sep {
+				return time.Time{}, errNoTimestamp
+			}
+			for i+1 < len(b) && isSpaceChar(b[i+1]) {
+				i++
+			}
+			c = 'T'
+		}
+		if c == 'T' {
+			sep = true
+		}
+		buf[n] = c
+		n++
+	}
+	s := string(buf[:n])
+
+	// timestamps without zone are written in log timezone
+	t, err := time.ParseInLocation(localTimeLayout, s, logZone())
+	if err != nil {
+		t, err = time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return t, err
+		}
+	}
+
+	return t.UTC(), nil
+}
+
+func configuredTimeSource() string {
+	timeMu.RLock()
+	defer timeMu.RUnlock()
+
+	return timeSource
+}
+
+func optTime(t time.Time) *time.Time {
+	// omit timestamps missing from the line
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func (lt logTimes) createdAt() (time.Time, string) {
+	// prefer configured time source, fall back to the other
+	switch true {
+	case configuredTimeSource() == NodeTimeSource && !lt.node.IsZero(), lt.launcher.IsZero():
+		return lt.node, NodeTimeSource
+	default:
+		return lt.launcher, LauncherTimeSource
+	}
+}
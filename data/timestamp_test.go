@@ -0,0 +1,146 @@
+package data
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestParseTimes(t *testing.T) {
+	// setup test vars
+	var log []byte
+	var got logTimes
+	var err error
+	var loc = time.Now().Location()
+
+	// test launcher and node timestamps
+	log = P2PNumPeersEx
+	got, err = parseTimes(log)
+	if err != nil {
+		t.Fatalf("data.parseTimes() returned error: %v", err)
+	}
+
+	wantLauncher := time.Date(2021, 8, 28, 9, 10, 32, 888e6, loc).UTC()
+	wantNode := time.Date(2021, 8, 28, 9, 10, 32, 0, loc).UTC()
+	if !got.launcher.Equal(wantLauncher) || !got.node.Equal(wantNode) {
+		t.Fatalf("data.parseTimes() returned: %v, %v, wanted: %v, %v", got.launcher, got.node, wantLauncher, wantNode)
+	}
+
+	// test utc timestamps with zone designator
+	log = []byte("[2021-09-29T20:54:51Z] [info] [ThetaEdgeLauncher] [2021-09-29T20:54:51Z]  INFO [p2p] ...")
+	got, err = parseTimes(log)
+	if err != nil {
+		t.Fatalf("data.parseTimes() returned error: %v", err)
+	}
+
+	want := time.Date(2021, 9, 29, 20, 54, 51, 0, time.UTC)
+	if !got.launcher.Equal(want) || !got.node.Equal(want) {
+		t.Fatalf("data.parseTimes() returned: %v, %v, wanted: %v, %v", got.launcher, got.node, want, want)
+	}
+
+	// test node timestamp only, ie after launcher tag
+	log = []byte("[info] [ThetaEdgeLauncher] [2021-08-28 09:10:32]  INFO [p2p] ...")
+	got, err = parseTimes(log)
+	if err != nil {
+		t.Fatalf("data.parseTimes() returned error: %v", err)
+	}
+
+	if !got.launcher.IsZero() || !got.node.Equal(wantNode) {
+		t.Fatalf("data.parseTimes() returned: %v, %v, wanted: %v, %v", got.launcher, got.node, time.Time{}, wantNode)
+	}
+
+	// test no timestamps
+	log = ErrLogExample
+	if got, err = parseTimes(log); err == nil {
+		t.Fatalf("data.parseTimes() returned: %v, wanted error: %v", got, err)
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	loc := time.Now().Location()
+	want := time.Date(2021, 8, 28, 9, 10, 32, 0, loc).UTC()
+
+	// test whitespace and separator variants
+	for _, s := range []string{"2021-08-28 09:10:32", "2021-08-28T09:10:32", "2021-08-28  09:10:32", " 2021-08-28\t09:10:32 "} {
+		got, err := parseTimestamp([]byte(s))
+		if err != nil {
+			t.Fatalf("data.parseTimestamp(%q) returned error: %v", s, err)
+		}
+
+		if !got.Equal(want) {
+			t.Fatalf("data.parseTimestamp(%q) returned: %v, wanted: %v", s, got, want)
+		}
+	}
+
+	// test invalid timestamps
+	for _, s := range []string{"", "info", "2021-08-28 09:10:32 PM", "2021-08-28 0910:32"} {
+		if got, err := parseTimestamp([]byte(s)); err == nil {
+			t.Fatalf("data.parseTimestamp(%q) returned: %v, wanted error", s, got)
+		}
+	}
+}
+
+func TestCreatedAt(t *testing.T) {
+	defer SetTimeSource("")
+
+	launcher := time.Date(2021, 8, 28, 9, 10, 32, 888e6, time.UTC)
+	node := time.Date(2021, 8, 28, 9, 10, 32, 0, time.UTC)
+
+	// test node time source
+	SetTimeSource(NodeTimeSource)
+	if got, src := (logTimes{launcher, node}).createdAt(); !got.Equal(node) || src != NodeTimeSource {
+		t.Fatalf("data.createdAt() returned: %v, %v, wanted: %v, %v", got, src, node, NodeTimeSource)
+	}
+
+	// test fall back to launcher time
+	if got, src := (logTimes{launcher: launcher}).createdAt(); !got.Equal(launcher) || src != LauncherTimeSource {
+		t.Fatalf("data.createdAt() returned: %v, %v, wanted: %v, %v", got, src, launcher, LauncherTimeSource)
+	}
+
+	// test launcher time source
+	SetTimeSource(LauncherTimeSource)
+	if got, src := (logTimes{launcher, node}).createdAt(); !got.Equal(launcher) || src != LauncherTimeSource {
+		t.Fatalf("data.createdAt() returned: %v, %v, wanted: %v, %v", got, src, launcher, LauncherTimeSource)
+	}
+
+	// test unknown time source
+	if err := SetTimeSource("server"); err == nil {
+		t.Fatalf("data.SetTimeSource() returned: %v, wanted error", err)
+	}
+}
+
+func TestOptTime(t *testing.T) {
+	// test missing timestamp omitted
+	if got := optTime(time.Time{}); got != nil {
+		t.Fatalf("data.optTime() returned: %v, wanted: nil", got)
+	}
+
+	// test timestamp kept
+	ts := time.Date(2021, 8, 28, 9, 10, 32, 0, time.UTC)
+	if got := optTime(ts); got == nil || !got.Equal(ts) {
+		t.Fatalf("data.optTime() returned: %v, wanted: %v", got, ts)
+	}
+
+	// test launcher time left out of records without one
+	p2p := &P2PNumPeers{Addr: "0x8d25fa2e7d", CreatedAt: ts, NodeTime: optTime(ts)}
+	b, err := p2p.ToJSON()
+	if err != nil {
+		t.Fatalf("data.P2PNumPeers.ToJSON() returned error: %v", err)
+	}
+	if bytes.Contains(b, []byte("launcher_time")) {
+		t.Fatalf("data.P2PNumPeers.ToJSON() returned: %s, wanted no launcher_time", b)
+	}
+}
+
+func TestLineTime(t *testing.T) {
+	// test node time of line per default time source
+	lt, _ := parseTimes(UMBroadcastedEx)
+	if got, err := LineTime(UMBroadcastedEx); err != nil || !got.Equal(lt.node) {
+		t.Fatalf("data.LineTime() returned: %v, %v, wanted: %v", got, err, lt.node)
+	}
+
+	// test line without timestamp
+	if got, err := LineTime([]byte("[uptime miner] Start new round: 7")); err == nil {
+		t.Fatalf("data.LineTime() returned: %v, wanted error", got)
+	}
+}
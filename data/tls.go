@@ -0,0 +1,109 @@
+package data
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	errNoCACerts = errors.New("no certificates in ca file")
+	errPinFailed = errors.New("server key does not match pin")
+)
+
+type TLSOptions struct {
+	CAFile     string   // pem bundle trusted in addition to system roots
+	CertFile   string   // client certificate for mutual tls
+	KeyFile    string   // client key for mutual tls
+	MinVersion string   // ie "1.2" or "1.3"
+	Pins       []string // base64 sha256 of server spki
+	Insecure   bool     // skip verification, dev only
+}
+
+func TLSOptionsFromEnv() TLSOptions {
+	var pins []string
+	for _, p := range strings.Split(os.Getenv("TLS_PIN_SHA256"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			pins = append(pins, p)
+		}
+	}
+
+	return TLSOptions{
+		CAFile:     os.Getenv("TLS_CA_FILE"),
+		CertFile:   os.Getenv("TLS_CERT_FILE"),
+		KeyFile:    os.Getenv("TLS_KEY_FILE"),
+		MinVersion: os.Getenv("TLS_MIN_VERSION"),
+		Pins:       pins,
+		Insecure:   os.Getenv("TLS_INSECURE_SKIP_VERIFY") == "true",
+	}
+}
+
+func NewTLSConfig(o TLSOptions) (*tls.Config, error) {
+	tc := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: o.Insecure,
+	}
+
+	switch o.MinVersion {
+	case "", "1.2":
+	case "1.3":
+		tc.MinVersion = tls.VersionTLS13
+	default:
+		s := fmt.Sprintf("unsupported tls version: %s", o.MinVersion)
+		return nil, errors.New(s)
+	}
+
+	// trust private ca along with system roots
+	if o.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errNoCACerts
+		}
+		tc.RootCAs = pool
+	}
+
+	// present client certificate to server
+	if o.CertFile != "" || o.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+
+	// check server key after chain verification, also when insecure
+	if len(o.Pins) > 0 {
+		pins := make(map[string]bool, len(o.Pins))
+		for _, p := range o.Pins {
+			pins[strings.TrimPrefix(p, "sha256/")] = true
+		}
+		tc.VerifyConnection = func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return errPinFailed
+			}
+			if !pins[spkiPin(cs.PeerCertificates[0])] {
+				return errPinFailed
+			}
+			return nil
+		}
+	}
+
+	return tc, nil
+}
+
+func spkiPin(cert *x509.Certificate) string {
+	h := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(h[:])
+}
@@ -0,0 +1,143 @@
+package data
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeServerCA(t *testing.T, srv *httptest.Server) string {
+	fp := filepath.Join(t.TempDir(), "ca.pem")
+	b := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	_ = os.WriteFile(fp, b, 0600)
+	return fp
+}
+
+func writeClientCert(t *testing.T) (string, string, *x509.Certificate) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "edgestats-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, _ := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	cert, _ := x509.ParseCertificate(der)
+	kder, _ := x509.MarshalECPrivateKey(key)
+
+	tmp := t.TempDir()
+	cf := filepath.Join(tmp, "client.pem")
+	kf := filepath.Join(tmp, "client.key")
+	_ = os.WriteFile(cf, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	_ = os.WriteFile(kf, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kder}), 0600)
+
+	return cf, kf, cert
+}
+
+func getWith(o TLSOptions, url string) error {
+	tc, err := NewTLSConfig(o)
+	if err != nil {
+		return err
+	}
+
+	c, err := NewHTTPClient(tc, TransportOptions{})
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Get(url)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	ca := writeServerCA(t, srv)
+
+	// test self signed server untrusted by default
+	if err := getWith(TLSOptions{}, srv.URL); err == nil {
+		t.Fatalf("data.NewTLSConfig() returned: %v, wanted error", err)
+	}
+
+	// test custom ca
+	if err := getWith(TLSOptions{CAFile: ca}, srv.URL); err != nil {
+		t.Fatalf("data.NewTLSConfig() returned error: %v", err)
+	}
+
+	// test insecure skip verify
+	if err := getWith(TLSOptions{Insecure: true}, srv.URL); err != nil {
+		t.Fatalf("data.NewTLSConfig() returned error: %v", err)
+	}
+
+	// test matching and mismatched spki pin
+	pin := spkiPin(srv.Certificate())
+	if err := getWith(TLSOptions{CAFile: ca, Pins: []string{"sha256/" + pin}}, srv.URL); err != nil {
+		t.Fatalf("data.NewTLSConfig() returned error: %v", err)
+	}
+
+	if err := getWith(TLSOptions{Insecure: true, Pins: []string{"AAAA"}}, srv.URL); err == nil {
+		t.Fatalf("data.NewTLSConfig() returned: %v, wanted error", err)
+	}
+
+	// test invalid settings
+	if _, err := NewTLSConfig(TLSOptions{MinVersion: "1.0"}); err == nil {
+		t.Fatalf("data.NewTLSConfig() returned: %v, wanted error", err)
+	}
+
+	if _, err := NewTLSConfig(TLSOptions{CAFile: filepath.Join(t.TempDir(), "none.pem")}); err == nil {
+		t.Fatalf("data.NewTLSConfig() returned: %v, wanted error", err)
+	}
+}
+
+func TestNewTLSConfigMutual(t *testing.T) {
+	cf, kf, cert := writeClientCert(t)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	srv.StartTLS()
+	defer srv.Close()
+	ca := writeServerCA(t, srv)
+
+	// test server requires client certificate
+	if err := getWith(TLSOptions{CAFile: ca}, srv.URL); err == nil {
+		t.Fatalf("data.NewTLSConfig() returned: %v, wanted error", err)
+	}
+
+	// test client certificate presented
+	if err := getWith(TLSOptions{CAFile: ca, CertFile: cf, KeyFile: kf, MinVersion: "1.3"}, srv.URL); err != nil {
+		t.Fatalf("data.NewTLSConfig() returned error: %v", err)
+	}
+}
+
+func TestTLSOptionsFromEnv(t *testing.T) {
+	defer os.Unsetenv("TLS_PIN_SHA256")
+	defer os.Unsetenv("TLS_INSECURE_SKIP_VERIFY")
+
+	os.Setenv("TLS_PIN_SHA256", "abc=, sha256/def=")
+	os.Setenv("TLS_INSECURE_SKIP_VERIFY", "true")
+
+	// test pins split and insecure flag
+	got := TLSOptionsFromEnv()
+	if len(got.Pins) != 2 || got.Pins[1] != "sha256/def=" || !got.Insecure {
+		t.Fatalf("data.TLSOptionsFromEnv() returned: %+v", got)
+	}
+}
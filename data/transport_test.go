@@ -0,0 +1,119 @@
+package data
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClientTimeout(t *testing.T) {
+	// setup test variables
+	var release = make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	// test hung server aborted by request timeout
+	c, err := NewHTTPClient(nil, TransportOptions{Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("data.NewHTTPClient() returned error: %v", err)
+	}
+
+	if resp, err := c.Get(srv.URL); err == nil {
+		resp.Body.Close()
+		t.Fatalf("data.NewHTTPClient() returned: %v, wanted timeout error", resp.Status)
+	}
+}
+
+func TestNewHTTPClientProxy(t *testing.T) {
+	// setup test variables
+	var gotURL string
+	var gotAuth string
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String()
+		gotAuth = r.Header.Get("Proxy-Authorization")
+	}))
+	defer proxy.Close()
+
+	o := DefaultTransportOptions()
+	o.ProxyURL = "http://user:pass@" + proxy.Listener.Addr().String()
+	c, err := NewHTTPClient(nil, o)
+	if err != nil {
+		t.Fatalf("data.NewHTTPClient() returned error: %v", err)
+	}
+
+	// test request routed through proxy with auth
+	resp, err := c.Get("http://edgestats.invalid/stats/uptimes/peers")
+	if err != nil {
+		t.Fatalf("data.NewHTTPClient() returned error: %v", err)
+	}
+	resp.Body.Close()
+
+	wantAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:pass"))
+	if gotURL != "http://edgestats.invalid/stats/uptimes/peers" || gotAuth != wantAuth {
+		t.Fatalf("data.NewHTTPClient() proxied: %v, %v, wanted: %v, %v", gotURL, gotAuth, "http://edgestats.invalid/stats/uptimes/peers", wantAuth)
+	}
+
+	// test invalid proxy url
+	o.ProxyURL = "proxy:3128"
+	if _, err := NewHTTPClient(nil, o); err == nil {
+		t.Fatalf("data.NewHTTPClient() returned: %v, wanted error", err)
+	}
+}
+
+func TestNewHTTPClientHTTP2(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	for _, disable := range []bool{false, true} {
+		o := DefaultTransportOptions()
+		o.DisableHTTP2 = disable
+		c, _ := NewHTTPClient(&tls.Config{InsecureSkipVerify: true}, o)
+
+		resp, err := c.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("data.NewHTTPClient() returned error: %v", err)
+		}
+		resp.Body.Close()
+
+		// test http2 negotiated unless disabled
+		if want := map[bool]int{false: 2, true: 1}[disable]; resp.ProtoMajor != want {
+			t.Fatalf("data.NewHTTPClient() used: %v, wanted major: %v", resp.Proto, want)
+		}
+	}
+}
+
+func TestTransportOptionsFromEnv(t *testing.T) {
+	defer os.Unsetenv("HTTP_TIMEOUT")
+	defer os.Unsetenv("HTTP2")
+
+	// test defaults
+	o, err := TransportOptionsFromEnv()
+	if err != nil || o != DefaultTransportOptions() {
+		t.Fatalf("data.TransportOptionsFromEnv() returned: %+v, %v", o, err)
+	}
+
+	// test configured values
+	os.Setenv("HTTP_TIMEOUT", "5s")
+	os.Setenv("HTTP2", "false")
+	o, err = TransportOptionsFromEnv()
+	if err != nil || o.Timeout != 5*time.Second || !o.DisableHTTP2 {
+		t.Fatalf("data.TransportOptionsFromEnv() returned: %+v, %v", o, err)
+	}
+
+	// test invalid duration
+	os.Setenv("HTTP_TIMEOUT", "forever")
+	if _, err := TransportOptionsFromEnv(); err == nil {
+		t.Fatalf("data.TransportOptionsFromEnv() returned: %v, wanted error", err)
+	}
+}
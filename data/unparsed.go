@@ -0,0 +1,268 @@
+package data
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	unparsedFileName = "unparsed.log"
+	maxUnparsedSize  = 4 * 1024 * 1024 // bytes, lines beyond are counted only
+)
+
+var (
+	unparsedMu     sync.Mutex // guards unparsed file and counts
+	unparsedFile   string
+	unparsedCounts = make(map[unparsedKey]int)
+	unparsedFull   int // lines counted but not written, file at max size
+)
+
+type unparsedKey struct {
+	parser string
+	reason string
+}
+
+type UnparsedLine struct {
+	Parser string    `json:"parser"`
+	Reason string    `json:"reason"`
+	Error  string    `json:"error"`
+	Line   string    `json:"line"`
+	At     time.Time `json:"quarantined_at"`
+}
+
+type UnparsedReason struct {
+	Parser string `json:"parser"`
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+type UnparsedStats struct {
+	File    string           `json:"file"`
+	Total   int              `json:"total"`
+	Skipped int              `json:"skipped"` // not written, file full
+	Reasons []UnparsedReason `json:"reasons,omitempty"`
+}
+
+func SetUnparsedFile(fp string) error {
+	// default to user cache dir, ie ~/.cache/edgestats/unparsed.log
+	if fp == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return err
+		}
+		fp = filepath.Join(dir, "edgestats", unparsedFileName)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fp), 0700); err != nil {
+		return err
+	}
+
+	unparsedMu.Lock()
+	defer unparsedMu.Unlock()
+
+	unparsedFile = fp
+	unparsedCounts = make(map[unparsedKey]int)
+	unparsedFull = 0
+
+	return nil
+}
+
+func UnparsedFile() string {
+	unparsedMu.Lock()
+	defer unparsedMu.Unlock()
+
+	return unparsedFile
+}
+
+func IsParseFailure(err error) bool {
+	// expected outcomes of a matched line are not failures
+	switch err {
+	case nil, errNoMatch, errThrottled, errNoAddr, errNoPeers, errAddrMismatch, errDuplicate, errQuarantined:
+		return false
+	}
+
+	return true
+}
+
+func QuarantineLine(p Parser, b []byte, err error) error {
+	// keep matched lines a parser failed on, ie changed log format
+	if !IsParseFailure(err) {
+		return nil
+	}
+
+	ul := UnparsedLine{
+		Parser: parserName(p),
+		Reason: normalizeMessage(err.Error()),
+		Error:  err.Error(),
+		Line:   string(b),
+		At:     time.Now().UTC(),
+	}
+
+	unparsedMu.Lock()
+	defer unparsedMu.Unlock()
+
+	unparsedCounts[unparsedKey{ul.Parser, ul.Reason}]++
+	if unparsedFile == "" {
+		return nil
+	}
+
+	d, err := json.Marshal(ul)
+	if err != nil {
+		return err
+	}
+
+	// bound file, ie log format changed for every line
+	ok, err := appendLine(unparsedFile, d)
+	if err == nil && !ok {
+		unparsedFull++
+	}
+
+	return err
+}
+
+func UnparsedStatus() interface{} {
+	unparsedMu.Lock()
+	defer unparsedMu.Unlock()
+
+	us := UnparsedStats{File: unparsedFile, Skipped: unparsedFull}
+	for k, n := range unparsedCounts {
+		us.Reasons = append(us.Reasons, UnparsedReason{Parser: k.parser, Reason: k.reason, Count: n})
+		us.Total += n
+	}
+	sortReasons(us.Reasons)
+
+	return us
+}
+
+func CountUnparsed(ls []UnparsedLine) []UnparsedReason {
+	// counts per parser and reason, most frequent first
+	idx := make(map[unparsedKey]int)
+	var rs []UnparsedReason
+	for _, l := range ls {
+		k := unparsedKey{l.Parser, l.Reason}
+		i, ok := idx[k]
+		if !ok {
+			i = len(rs)
+			idx[k] = i
+			rs = append(rs, UnparsedReason{Parser: l.Parser, Reason: l.Reason})
+		}
+		rs[i].Count++
+	}
+	sortReasons(rs)
+
+	return rs
+}
+
+func ReadUnparsed(fp string) ([]UnparsedLine, error) {
+	f, err := os.Open(fp)
+	if os.IsNotExist(err) {
+		return nil, nil // nothing quarantined yet
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var ls []UnparsedLine
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var l UnparsedLine
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			continue // skip partial writes
+		}
+		ls = append(ls, l)
+	}
+
+	return ls, scanner.Err()
+}
+
+func WriteUnparsed(fp string, ls []UnparsedLine) error {
+	// replace file, ie keep lines still failing after retry
+	var ds [][]byte
+	for _, l := range ls {
+		d, err := json.Marshal(l)
+		if err != nil {
+			return err
+		}
+		ds = append(ds, d)
+	}
+
+	return replaceLines(fp, ds)
+}
+
+func appendLine(fp string, d []byte) (bool, error) {
+	// append json line, false once file at max size, shared by quarantine files
+	f, err := os.OpenFile(fp, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+	if info.Size()+int64(len(d)+1) > maxUnparsedSize {
+		return false, nil
+	}
+
+	_, err = f.Write(append(d, '\n'))
+	return err == nil, err
+}
+
+func replaceLines(fp string, ds [][]byte) error {
+	// write tmp file then rename, ie no partial file on crash
+	tmp := fp + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	for _, d := range ds {
+		w.Write(append(d, '\n'))
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, fp)
+}
+
+func parserName(p Parser) string {
+	switch p.(type) {
+	case *UMBroadcast:
+		return FilterName(UMFilter)
+	case *P2PNumPeers:
+		return FilterName(P2PFilter)
+	case *NSProgress:
+		return FilterName(NSFilter)
+	case *CSProgress:
+		return FilterName(CSFilter)
+	case *Incident:
+		return FilterName(LevelFilter)
+	default:
+		return FilterName(ErrFilter)
+	}
+}
+
+func sortReasons(rs []UnparsedReason) {
+	sort.Slice(rs, func(i, j int) bool {
+		if rs[i].Count != rs[j].Count {
+			return rs[i].Count > rs[j].Count
+		}
+		if rs[i].Parser != rs[j].Parser {
+			return rs[i].Parser < rs[j].Parser
+		}
+		return rs[i].Reason < rs[j].Reason
+	})
+}
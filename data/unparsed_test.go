@@ -0,0 +1,126 @@
+package data
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var (
+	UMUnknownKeyEx = []byte("[2021-08-28 09:00:26.951] [info] [ThetaEdgeLauncher] [2021-08-28 09:00:26]  INFO [uptime miner] Broadcasted vote: EENVote{Block: 0x6d0ae6, Height: 11759001, Round: 7, Address: 0x8d25fa2e7d}")
+)
+
+func TestIsParseFailure(t *testing.T) {
+	// test expected outcomes not failures
+	for _, err := range []error{nil, errNoMatch, errThrottled, errNoAddr, errNoPeers, errAddrMismatch, errDuplicate, errQuarantined} {
+		if IsParseFailure(err) {
+			t.Fatalf("data.IsParseFailure(%v) returned: %v, wanted: %v", err, true, false)
+		}
+	}
+
+	if !IsParseFailure(errors.New("error no match: Round, found: 7")) {
+		t.Fatalf("data.IsParseFailure() returned: %v, wanted: %v", false, true)
+	}
+}
+
+func TestQuarantineLine(t *testing.T) {
+	// setup test variables
+	var fp = filepath.Join(t.TempDir(), unparsedFileName)
+
+	defer func() {
+		unparsedMu.Lock()
+		unparsedFile = ""
+		unparsedMu.Unlock()
+	}()
+	if err := SetUnparsedFile(fp); err != nil {
+		t.Fatalf("data.SetUnparsedFile() returned error: %v", err)
+	}
+
+	// test unknown key quarantined twice, expected errors not
+	for i := 0; i < 2; i++ {
+		p := NewUMBroadcast()
+		if err := QuarantineLine(p, UMUnknownKeyEx, p.Parse(UMUnknownKeyEx)); err != nil {
+			t.Fatalf("data.QuarantineLine() returned error: %v", err)
+		}
+	}
+	QuarantineLine(NewP2PNumPeers(), P2PNumPeersEx, errThrottled)
+
+	ls, err := ReadUnparsed(fp)
+	if err != nil || len(ls) != 2 {
+		t.Fatalf("data.ReadUnparsed() returned: %v, %v, wanted 2 lines", ls, err)
+	}
+	if ls[0].Parser != "uptime" || ls[0].Reason != "error no match: Round, found: #" || ls[0].Line != string(UMUnknownKeyEx) {
+		t.Fatalf("data.QuarantineLine() wrote: %+v", ls[0])
+	}
+
+	// test counts per reason
+	us := UnparsedStatus().(UnparsedStats)
+	if us.Total != 2 || len(us.Reasons) != 1 || us.Reasons[0].Count != 2 || us.File != fp {
+		t.Fatalf("data.UnparsedStatus() returned: %+v", us)
+	}
+	if rs := CountUnparsed(ls); len(rs) != 1 || rs[0].Count != 2 {
+		t.Fatalf("data.CountUnparsed() returned: %+v", rs)
+	}
+
+	// test rewrite keeps given lines only
+	if err := WriteUnparsed(fp, ls[:1]); err != nil {
+		t.Fatalf("data.WriteUnparsed() returned error: %v", err)
+	}
+	if ls, _ = ReadUnparsed(fp); len(ls) != 1 {
+		t.Fatalf("data.ReadUnparsed() returned: %v, wanted 1 line", ls)
+	}
+
+	// test none file path is empty
+	if ls, err = ReadUnparsed(fp + ".none"); err != nil || ls != nil {
+		t.Fatalf("data.ReadUnparsed() returned: %v, %v", ls, err)
+	}
+}
+
+func TestQuarantineLineNormalLines(t *testing.T) {
+	// setup test variables
+	var fp = filepath.Join(t.TempDir(), unparsedFileName)
+	var lines = [][]byte{
+		OtherLogEx,
+		[]byte("[2021-08-28 09:10:33.415] [info] [ThetaEdgeLauncher] [2021-08-28 09:10:33]  INFO [consensus] Vote received, vote: {Block: 0x6d0ae6, ID: 0x2e833968e5}"),
+		[]byte("[2021-08-28 09:10:33.415] [info] [ThetaEdgeLauncher] [2021-08-28 09:10:33]  INFO [netsync] Peer disconnected, peer: 0x9ab1c2d3e4"),
+		NSLogEx, CSEpochEx,
+	}
+
+	defer SetUnparsedFile(fp + ".off")
+	SetUnparsedFile(fp)
+
+	// test ordinary consensus and netsync lines not quarantined
+	for _, b := range lines {
+		p := NewParser(Filter(b))
+		QuarantineLine(p, b, p.Parse(b))
+	}
+
+	if us := UnparsedStatus().(UnparsedStats); us.Total != 0 {
+		t.Fatalf("data.UnparsedStatus() returned: %+v, wanted none", us)
+	}
+	if ls, _ := ReadUnparsed(fp); len(ls) != 0 {
+		t.Fatalf("data.ReadUnparsed() returned: %v, wanted none", ls)
+	}
+}
+
+func TestQuarantineLineMaxSize(t *testing.T) {
+	// setup test variables
+	var fp = filepath.Join(t.TempDir(), unparsedFileName)
+	var err = errors.New("error no match: Round, found: 7")
+
+	defer SetUnparsedFile(fp + ".off")
+	SetUnparsedFile(fp)
+
+	// test full file counted only
+	_ = os.WriteFile(fp, bytes.Repeat([]byte("x"), maxUnparsedSize-10), 0600)
+	QuarantineLine(NewUMBroadcast(), UMUnknownKeyEx, err)
+
+	if info, _ := os.Stat(fp); info.Size() != maxUnparsedSize-10 {
+		t.Fatalf("data.QuarantineLine() wrote past max size: %v", info.Size())
+	}
+	if us := UnparsedStatus().(UnparsedStats); us.Total != 1 || us.Skipped != 1 {
+		t.Fatalf("data.UnparsedStatus() returned: %+v, wanted skipped: 1", us)
+	}
+}
@@ -5,16 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 )
 
-const (
-	umBroadcastRE = `(?m)(?P<key>\w+):\s+(?P<value>\w+)\,?`
-)
-
 const (
 	umErrFilter = iota
 	umReceivedBlockFilter
@@ -31,15 +25,31 @@ var (
 	broadcastedVote         = []byte("Broadcasted vote")
 )
 
+var (
+	keyVote              = []byte("vote")
+	keyBlock             = []byte("Block")
+	keyHeight            = []byte("Height")
+	keyAddress           = []byte("Address")
+	keySignature         = []byte("Signature")
+	keyCreationTimestamp = []byte("CreationTimestamp")
+	keyBlockLower        = []byte("block")
+	keyHeightLower       = []byte("height")
+)
+
 type UMBroadcast struct {
-	Block           string    `json:"block"`
-	Height          int       `json:"height"`
-	Addr            string    `json:"address"`
-	Signature       string    `json:"signature"`
-	Timestamp       int       `json:"timestamp"`
-	NumPeers        int       `json:"num_peers"`
-	SufficientPeers int       `json:"sufficient_peers"`
-	CreatedAt       time.Time `json:"created_at"`
+	Block           string     `json:"block"`
+	Height          int        `json:"height"`
+	Addr            string     `json:"address"`
+	Signature       string     `json:"signature"`
+	Timestamp       int        `json:"timestamp"`
+	NumPeers        int        `json:"num_peers"`
+	SufficientPeers int        `json:"sufficient_peers"`
+	CreatedAt       time.Time  `json:"created_at"`
+	LauncherTime    *time.Time `json:"launcher_time,omitempty"`
+	NodeTime        *time.Time `json:"node_time,omitempty"`
+	TimeSource      string     `json:"time_source"`
+	ZoneMismatch    bool       `json:"zone_mismatch,omitempty"`
+	Issues          []string   `json:"issues,omitempty"` // set by validation
 }
 
 func NewUMBroadcast() *UMBroadcast {
@@ -50,67 +60,85 @@ func (um *UMBroadcast) ToJSON() ([]byte, error) {
 	return json.Marshal(um)
 }
 
+func (um *UMBroadcast) ID() string {
+	// same vote may be logged as new block and broadcast
+	return fmt.Sprintf("vote:%s:%d:%s", um.Addr, um.Height, um.Signature)
+}
+
 func (um *UMBroadcast) Parse(b []byte) error {
-	if filterUMLogType(b) != umBroadcastedVoteFilter {
-		return errors.New("no match")
+	switch kind := filterUMLogType(b); kind {
+	case umBroadcastedVoteFilter:
+	case umReceivedBlockFilter, umNewRoundFilter:
+		// tracked for consensus progress only
+		observeProgress(b, kind)
+		return errNoMatch
+	default:
+		return errNoMatch
 	}
 
-	re := regexp.MustCompile(umBroadcastRE)
-	ml := re.FindAllSubmatch(b, -1)
-
 	var vk string
 	var vh int
 	var va string
 	var vs string
 	var vt int
 
-	for _, i := range ml {
+	for k, v, rest := nextKeyValue(b); k != nil; k, v, rest = nextKeyValue(rest) {
 		switch true {
-		case bytes.Equal(i[1], []byte("vote")):
+		case bytes.Equal(k, keyVote):
 			continue // perhaps check that value is EENVote?
-		case bytes.Equal(i[1], []byte("Block")):
-			vk = string(i[2])
-		case bytes.Equal(i[1], []byte("Height")):
-			v, err := strconv.Atoi(string(i[2]))
+		case bytes.Equal(k, keyBlock):
+			vk = string(v)
+		case bytes.Equal(k, keyHeight):
+			n, err := atoi(v)
 			if err != nil {
 				return err
 			}
-			vh = v
-		case bytes.Equal(i[1], []byte("Address")):
-			v := string(i[2])
-			va = strings.ToLower(v)
-		case bytes.Equal(i[1], []byte("Signature")):
-			vs = string(i[2])
-		case bytes.Equal(i[1], []byte("CreationTimestamp")):
-			v, err := strconv.Atoi(string(i[2]))
+			vh = n
+		case bytes.Equal(k, keyAddress):
+			va = strings.ToLower(string(v))
+		case bytes.Equal(k, keySignature):
+			vs = string(v)
+		case bytes.Equal(k, keyCreationTimestamp):
+			n, err := atoi(v)
 			if err != nil {
 				return err
 			}
-			vt = v
-		case bytes.Equal(i[1], []byte("block")):
+			vt = n
+		case bytes.Equal(k, keyBlockLower):
 			continue // only seen in start new block log
-		case bytes.Equal(i[1], []byte("height")):
+		case bytes.Equal(k, keyHeightLower):
 			continue // only seen in start new block log
 		default: // no match found
-			s := fmt.Sprintf("error no match: %s, found: %s", i[1], i[2])
+			s := fmt.Sprintf("error no match: %s, found: %s", k, v)
 			return errors.New(s)
 		}
 	}
 
-	t, err := parseTime(b)
+	lt, err := parseTimes(b)
 	if err != nil {
 		return err
 	}
+	t, ts := lt.createdAt()
+
+	// learn log timezone offset from vote unix time
+	if d, err := detectOffset(t, vt); err == nil && setDetectedOffset(d) {
+		lt, err = parseTimes(b)
+		if err != nil {
+			return err
+		}
+		t, ts = lt.createdAt()
+	}
 
-	// populate node address variable
-	if err := setAddr(va); err != nil {
+	// populate node address variable, else check configured
+	if err := checkAddr(va, vh, t); err != nil {
 		return err
 	}
 
 	// return error if not yet bootstrapped with peers
 	// perhaps rethink this condition
-	if nodePeers == 0 && sufficientPeers == 0 {
-		return errors.New("no peers bootstrapped")
+	np, sp := getPeers()
+	if np == 0 && sp == 0 {
+		return errNoPeers
 	}
 
 	um.Block = vk
@@ -118,9 +146,15 @@ func (um *UMBroadcast) Parse(b []byte) error {
 	um.Addr = va
 	um.Signature = vs
 	um.Timestamp = vt
-	um.NumPeers = nodePeers
-	um.SufficientPeers = sufficientPeers
+	um.NumPeers = np
+	um.SufficientPeers = sp
 	um.CreatedAt = t
+	um.LauncherTime = optTime(lt.launcher)
+	um.NodeTime = optTime(lt.node)
+	um.TimeSource = ts
+	markLogTime(t)
+	um.ZoneMismatch = zoneMismatch(t, vt)
+	rewards.observe(vh, vt)
 
 	return nil
 }
@@ -142,10 +176,20 @@ func filterUMLogType(b []byte) int {
 }
 
 func setAddr(addr string) error {
+	nodeMu.Lock()
+	defer nodeMu.Unlock()
+
 	nodeAddr = addr
 	if nodeAddr == "" {
-		return errors.New("no address bootstrapped")
+		return errNoAddr
 	}
 
 	return nil
 }
+
+func getAddr() string {
+	nodeMu.RLock()
+	defer nodeMu.RUnlock()
+
+	return nodeAddr
+}
@@ -3,7 +3,6 @@ package data
 import (
 	"reflect"
 	"testing"
-	"time"
 )
 
 var (
@@ -29,7 +28,7 @@ func TestUMBroadcastParse(t *testing.T) {
 	var log []byte
 	var got = NewUMBroadcast()
 	var want = NewUMBroadcast()
-	var tt time.Time
+	var lt logTimes
 	var err error
 
 	// sim bootstrap peers
@@ -37,7 +36,7 @@ func TestUMBroadcastParse(t *testing.T) {
 
 	// test parse broadcastedVote
 	log = UMBroadcastedEx
-	tt, _ = parseTime(log)
+	lt, _ = parseTimes(log)
 	want = &UMBroadcast{
 		Block:           "0x6d0ae6972cd670a8f7dfd628ef516051d0fd699906c55f80cff12540bd3786a8",
 		Height:          11759001,
@@ -46,7 +45,11 @@ func TestUMBroadcastParse(t *testing.T) {
 		Timestamp:       1630155386,
 		NumPeers:        16,
 		SufficientPeers: 16,
-		CreatedAt:       tt,
+		CreatedAt:       lt.node,
+		ZoneMismatch:    zoneMismatch(lt.node, 1630155386),
+		LauncherTime:    optTime(lt.launcher),
+		NodeTime:        optTime(lt.node),
+		TimeSource:      NodeTimeSource,
 	}
 
 	if err = got.Parse(log); err != nil {
@@ -59,7 +62,7 @@ func TestUMBroadcastParse(t *testing.T) {
 
 	// test parse newBlock
 	log = UMNewBlockEx
-	tt, _ = parseTime(log)
+	lt, _ = parseTimes(log)
 	want = &UMBroadcast{
 		Block:           "0xfdca353dd0dcb8d193b1d731e065db547dcdfc6b0af20efdabb1eeff0f430cf2",
 		Height:          11759201,
@@ -68,7 +71,11 @@ func TestUMBroadcastParse(t *testing.T) {
 		Timestamp:       1630156631,
 		NumPeers:        16,
 		SufficientPeers: 16,
-		CreatedAt:       tt,
+		CreatedAt:       lt.node,
+		ZoneMismatch:    zoneMismatch(lt.node, 1630156631),
+		LauncherTime:    optTime(lt.launcher),
+		NodeTime:        optTime(lt.node),
+		TimeSource:      NodeTimeSource,
 	}
 
 	if err = got.Parse(log); err != nil {
@@ -161,3 +168,18 @@ func TestSetAddr(t *testing.T) {
 		t.Fatalf("data.setAddr() returned: %v, wanted: %v", got, want)
 	}
 }
+
+func BenchmarkUMBroadcastParse(b *testing.B) {
+	// sim bootstrap peers
+	setPeers(16, 16)
+
+	um := NewUMBroadcast()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(UMBroadcastedEx)))
+
+	for i := 0; i < b.N; i++ {
+		if err := um.Parse(UMBroadcastedEx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
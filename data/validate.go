@@ -0,0 +1,252 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ValidateOff        = "off"
+	ValidateTag        = "tag"
+	ValidateQuarantine = "quarantine"
+
+	suspiciousFileName = "suspicious.log"
+	defaultMaxSkew     = 10 * time.Minute // vote broadcast may lag creation by minutes
+)
+
+var (
+	errQuarantined = errors.New("record quarantined")
+)
+
+var (
+	validateMu     sync.Mutex // guards validation state below
+	validateMode   = ValidateTag
+	suspiciousFile string
+	maxSkew        = defaultMaxSkew
+	lastHeights    = make(map[string]int) // newest vote height per address
+)
+
+type Validator interface {
+	Validate() []string // tags record with issues found
+}
+
+type suspicious struct {
+	URL        string          `json:"url"`
+	Issues     []string        `json:"issues"`
+	Record     json.RawMessage `json:"record"`
+	Quarantine time.Time       `json:"quarantined_at"`
+}
+
+func SetValidation(mode, fp string) error {
+	// tag sends with issues, quarantine writes to file instead
+	switch mode {
+	case "":
+		mode = ValidateTag
+	case ValidateOff, ValidateTag, ValidateQuarantine:
+	default:
+		s := fmt.Sprintf("invalid VALIDATION: %s", mode)
+		return errors.New(s)
+	}
+
+	// default to user cache dir, ie ~/.cache/edgestats/suspicious.log
+	if mode == ValidateQuarantine && fp == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return err
+		}
+		fp = filepath.Join(dir, "edgestats", suspiciousFileName)
+	}
+	if fp != "" {
+		if err := os.MkdirAll(filepath.Dir(fp), 0700); err != nil {
+			return err
+		}
+	}
+
+	validateMu.Lock()
+	defer validateMu.Unlock()
+
+	validateMode = mode
+	suspiciousFile = fp
+	lastHeights = make(map[string]int)
+
+	return nil
+}
+
+func ValidationSkewFromEnv() (time.Duration, error) {
+	v := os.Getenv("VALIDATION_MAX_SKEW")
+	if v == "" {
+		return defaultMaxSkew, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		s := fmt.Sprintf("invalid VALIDATION_MAX_SKEW: %s", v)
+		return 0, errors.New(s)
+	}
+
+	return d, nil
+}
+
+func SetValidationSkew(d time.Duration) {
+	validateMu.Lock()
+	defer validateMu.Unlock()
+
+	maxSkew = d
+}
+
+func IsQuarantined(err error) bool {
+	return err == errQuarantined
+}
+
+func (um *UMBroadcast) Validate() []string {
+	var issues []string
+
+	if um.Height <= 0 {
+		issues = append(issues, "missing height")
+	}
+	if !isHexAddr(strings.ToLower(um.Block)) {
+		issues = append(issues, "block not hex")
+	}
+	if !isHexAddr(um.Addr) {
+		issues = append(issues, "address not hex")
+	}
+	if !isHexAddr("0x" + strings.ToLower(strings.TrimPrefix(um.Signature, "0x"))) {
+		issues = append(issues, "signature not hex")
+	}
+	if um.Timestamp <= 0 {
+		issues = append(issues, "missing timestamp")
+	} else if d := um.CreatedAt.Sub(time.Unix(int64(um.Timestamp), 0)); d > skewBound() || d < -skewBound() {
+		// created at already offset corrected, ie any hours off is skew
+		issues = append(issues, "timestamp skew")
+	}
+
+	// heights per address only move forward, watermark advanced on send only
+	if um.Height > 0 && um.Addr != "" {
+		validateMu.Lock()
+		last, ok := lastHeights[um.Addr]
+		validateMu.Unlock()
+		if ok && um.Height < last {
+			issues = append(issues, fmt.Sprintf("height went backwards from %d", last))
+		}
+	}
+
+	um.Issues = issues
+	return issues
+}
+
+func (p2p *P2PNumPeers) Validate() []string {
+	var issues []string
+
+	if !isHexAddr(p2p.Addr) {
+		issues = append(issues, "address not hex")
+	}
+	if p2p.SufficientPeers <= 0 {
+		issues = append(issues, "missing sufficient peers")
+	}
+	if p2p.NumPeers < 0 {
+		issues = append(issues, "negative peers")
+	}
+
+	p2p.Issues = issues
+	return issues
+}
+
+func ResetHeights() {
+	// forget height watermark, ie replayed logs start over
+	validateMu.Lock()
+	defer validateMu.Unlock()
+
+	lastHeights = make(map[string]int)
+}
+
+func acceptHeight(p Parser) {
+	// record queued for sending, ie never from preview, dry run or retry
+	um, ok := p.(*UMBroadcast)
+	if !ok || um.Height <= 0 || um.Addr == "" {
+		return
+	}
+
+	validateMu.Lock()
+	defer validateMu.Unlock()
+
+	if um.Height > lastHeights[um.Addr] {
+		lastHeights[um.Addr] = um.Height
+	}
+}
+
+func skewBound() time.Duration {
+	validateMu.Lock()
+	defer validateMu.Unlock()
+
+	return maxSkew
+}
+
+func validate(p Parser) error {
+	// error only when record quarantined
+	validateMu.Lock()
+	mode, fp := validateMode, suspiciousFile
+	validateMu.Unlock()
+
+	v, ok := p.(Validator)
+	if !ok || mode == ValidateOff {
+		return nil
+	}
+
+	issues := v.Validate()
+	if len(issues) == 0 || mode == ValidateTag {
+		return nil
+	}
+
+	if err := quarantine(fp, p, issues); err != nil {
+		return err
+	}
+
+	return errQuarantined
+}
+
+func quarantine(fp string, p Parser, issues []string) error {
+	d, err := p.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(suspicious{
+		URL:        getServiceURI(p),
+		Issues:     issues,
+		Record:     d,
+		Quarantine: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// bound file like unparsed.log, oldest half dropped once full
+	ok, err := appendLine(fp, b)
+	if err != nil || ok {
+		return err
+	}
+	if err := trimSuspicious(fp); err != nil {
+		return err
+	}
+
+	_, err = appendLine(fp, b)
+	return err
+}
+
+func trimSuspicious(fp string) error {
+	// keep newest half of records, ie newest issues most useful
+	b, err := os.ReadFile(fp)
+	if err != nil {
+		return err
+	}
+	ds := bytes.Split(bytes.TrimSuffix(b, []byte("\n")), []byte("\n"))
+
+	return replaceLines(fp, ds[len(ds)/2:])
+}
@@ -0,0 +1,226 @@
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUMBroadcastValidate(t *testing.T) {
+	defer SetValidation("", "")
+	SetValidation(ValidateTag, "")
+
+	// setup test variables
+	var ct = time.Unix(1630156631, 0).UTC()
+	var um = &UMBroadcast{Block: "0xfdca35", Height: 11759201, Addr: "0x8d25fa2e7d", Signature: "E1A06D0AE697786A8", Timestamp: 1630156631, CreatedAt: ct}
+
+	// test valid vote has no issues
+	if got := um.Validate(); len(got) != 0 {
+		t.Fatalf("data.UMBroadcast.Validate() returned: %v, wanted none", got)
+	}
+
+	// test validate alone leaves watermark, ie preview and dry run
+	older := *um
+	older.Height = 11759001
+	if got := older.Validate(); len(got) != 0 {
+		t.Fatalf("data.UMBroadcast.Validate() returned: %v, wanted none", got)
+	}
+	acceptHeight(um)
+
+	// test bad fields and lower height tagged
+	bad := &UMBroadcast{Block: "fdca35", Height: 11759001, Addr: "0x8d25fa2e7d", Signature: "xyz", Timestamp: 1630156631, CreatedAt: ct.Add(72 * time.Hour)}
+	got := bad.Validate()
+	want := []string{"block not hex", "signature not hex", "timestamp skew", "height went backwards from 11759201"}
+	if len(got) != len(want) {
+		t.Fatalf("data.UMBroadcast.Validate() returned: %v, wanted: %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("data.UMBroadcast.Validate() returned: %v, wanted: %v", got, want)
+		}
+	}
+	if len(bad.Issues) != len(want) {
+		t.Fatalf("data.UMBroadcast.Issues: %v, wanted: %v", bad.Issues, want)
+	}
+}
+
+func TestUMBroadcastValidateSkew(t *testing.T) {
+	defer SetValidation("", "")
+	defer SetValidationSkew(defaultMaxSkew)
+	SetValidation(ValidateTag, "")
+
+	// setup test variables
+	var ct = time.Unix(1630156631, 0).UTC()
+	var tests = []struct {
+		d    time.Duration
+		skew bool
+	}{
+		{4 * time.Minute, false}, // broadcast after creation
+		{-4 * time.Minute, false},
+		{time.Hour, true}, // aligned to zone offsets, still skew
+		{9 * time.Hour, true},
+		{-5*time.Hour - 30*time.Minute, true},
+	}
+
+	// test whole hour offsets flagged, not only unaligned ones
+	for i, tt := range tests {
+		um := &UMBroadcast{Block: "0xfdca35", Height: 11759201 + i, Addr: "0x8d25fa2e7d", Signature: "E1A06D0AE697786A8", Timestamp: 1630156631, CreatedAt: ct.Add(tt.d)}
+		got := um.Validate()
+		if skew := len(got) == 1 && got[0] == "timestamp skew"; skew != tt.skew || (!tt.skew && len(got) != 0) {
+			t.Fatalf("data.UMBroadcast.Validate() with %v returned: %v, wanted skew: %v", tt.d, got, tt.skew)
+		}
+	}
+
+	// test configured bound
+	SetValidationSkew(2 * time.Hour)
+	um := &UMBroadcast{Block: "0xfdca35", Height: 11759301, Addr: "0x8d25fa2e7d", Signature: "E1A06D0AE697786A8", Timestamp: 1630156631, CreatedAt: ct.Add(time.Hour)}
+	if got := um.Validate(); len(got) != 0 {
+		t.Fatalf("data.UMBroadcast.Validate() returned: %v, wanted none", got)
+	}
+}
+
+func TestValidationSkewFromEnv(t *testing.T) {
+	defer os.Unsetenv("VALIDATION_MAX_SKEW")
+
+	// test default
+	if got, _ := ValidationSkewFromEnv(); got != defaultMaxSkew {
+		t.Fatalf("data.ValidationSkewFromEnv() returned: %v, wanted: %v", got, defaultMaxSkew)
+	}
+
+	// test invalid skew
+	os.Setenv("VALIDATION_MAX_SKEW", "-1m")
+	if got, err := ValidationSkewFromEnv(); err == nil {
+		t.Fatalf("data.ValidationSkewFromEnv() returned: %v, wanted error", got)
+	}
+}
+
+func TestP2PNumPeersValidate(t *testing.T) {
+	// test missing address and sufficient peers tagged
+	p2p := &P2PNumPeers{NumPeers: 16}
+	if got := p2p.Validate(); len(got) != 2 {
+		t.Fatalf("data.P2PNumPeers.Validate() returned: %v, wanted 2 issues", got)
+	}
+
+	p2p = &P2PNumPeers{Addr: "0x8d25fa2e7d", NumPeers: 16, SufficientPeers: 16}
+	if got := p2p.Validate(); len(got) != 0 {
+		t.Fatalf("data.P2PNumPeers.Validate() returned: %v, wanted none", got)
+	}
+}
+
+func TestSetValidation(t *testing.T) {
+	defer SetValidation("", "")
+
+	// test invalid mode
+	if err := SetValidation("drop", ""); err == nil {
+		t.Fatalf("data.SetValidation() returned: %v, wanted error", err)
+	}
+}
+
+func TestEncodeQuarantine(t *testing.T) {
+	// setup test variables
+	var fp = filepath.Join(t.TempDir(), suspiciousFileName)
+	var p2p = &P2PNumPeers{NumPeers: 16, CreatedAt: time.Now()}
+
+	defer SetValidation("", "")
+	if err := SetValidation(ValidateQuarantine, fp); err != nil {
+		t.Fatalf("data.SetValidation() returned error: %v", err)
+	}
+
+	prev := dedup
+	dedup = newDedupCache(defaultDedupSize, "")
+	defer func() { dedup = prev }()
+
+	// test suspicious record not encoded
+	if _, err := Encode(p2p); !IsQuarantined(err) {
+		t.Fatalf("data.Encode() returned: %v, wanted quarantined", err)
+	}
+
+	// test quarantined record kept as seen
+	if _, err := Encode(p2p); !IsDuplicate(err) {
+		t.Fatalf("data.Encode() returned: %v, wanted duplicate", err)
+	}
+
+	f, err := os.Open(fp)
+	if err != nil {
+		t.Fatalf("os.Open() returned error: %v", err)
+	}
+	defer f.Close()
+
+	var got suspicious
+	sc := bufio.NewScanner(f)
+	if !sc.Scan() || json.Unmarshal(sc.Bytes(), &got) != nil || len(got.Issues) != 2 || got.URL != p2pNumPeersServiceURL {
+		t.Fatalf("data.quarantine() wrote: %s", sc.Bytes())
+	}
+
+	// test tagged record encoded with issues
+	SetValidation(ValidateTag, "")
+	p2p.CreatedAt = p2p.CreatedAt.Add(time.Second)
+	j, err := Encode(p2p)
+	if err != nil {
+		t.Fatalf("data.Encode() returned error: %v", err)
+	}
+	var tagged P2PNumPeers
+	if json.Unmarshal(j.Body, &tagged); len(tagged.Issues) != 2 {
+		t.Fatalf("data.Encode() returned body: %s", j.Body)
+	}
+}
+
+func TestEncodeHeightWatermark(t *testing.T) {
+	// setup test variables
+	var ct = time.Unix(1630156631, 0).UTC()
+	var vote = func(h int) *UMBroadcast {
+		return &UMBroadcast{Block: "0xfdca35", Height: h, Addr: "0x8d25fa2e7d", Signature: "E1A06D0AE697786A8", Timestamp: 1630156631, CreatedAt: ct}
+	}
+
+	defer SetValidation("", "")
+	SetValidation(ValidateTag, "")
+	prev := dedup
+	dedup = newDedupCache(defaultDedupSize, "")
+	defer func() { dedup = prev }()
+
+	// test preview leaves watermark
+	Preview(vote(11759201))
+	if got := vote(11759001).Validate(); len(got) != 0 {
+		t.Fatalf("data.UMBroadcast.Validate() after preview returned: %v, wanted none", got)
+	}
+
+	// test encode advances watermark
+	if _, err := Encode(vote(11759201)); err != nil {
+		t.Fatalf("data.Encode() returned error: %v", err)
+	}
+	if got := vote(11759001).Validate(); len(got) != 1 {
+		t.Fatalf("data.UMBroadcast.Validate() after encode returned: %v, wanted 1 issue", got)
+	}
+
+	// test reset for replay
+	ResetHeights()
+	if got := vote(11759001).Validate(); len(got) != 0 {
+		t.Fatalf("data.UMBroadcast.Validate() after reset returned: %v, wanted none", got)
+	}
+}
+
+func TestQuarantineTrim(t *testing.T) {
+	// setup test variables
+	var fp = filepath.Join(t.TempDir(), suspiciousFileName)
+	var line = append(bytes.Repeat([]byte("x"), 1023), '\n')
+	var p2p = &P2PNumPeers{NumPeers: 16, CreatedAt: time.Now()}
+
+	// sim full file
+	if err := os.WriteFile(fp, bytes.Repeat(line, maxUnparsedSize/len(line)), 0600); err != nil {
+		t.Fatalf("os.WriteFile() returned error: %v", err)
+	}
+
+	// test oldest half dropped, newest record written
+	if err := quarantine(fp, p2p, []string{"missing sufficient peers"}); err != nil {
+		t.Fatalf("data.quarantine() returned error: %v", err)
+	}
+
+	b, _ := os.ReadFile(fp)
+	if len(b) > maxUnparsedSize/2+2048 || !bytes.Contains(b, []byte("missing sufficient peers")) {
+		t.Fatalf("data.quarantine() left file of size: %v", len(b))
+	}
+}
@@ -0,0 +1,101 @@
+package data
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	autoZone      = "auto"
+	zoneStep      = 15 * time.Minute // smallest real world utc offset step
+	maxZoneOffset = 14 * time.Hour
+	maxZoneDrift  = 5 * time.Minute // allowed log vs vote timestamp drift
+)
+
+var (
+	zoneMu         sync.RWMutex
+	logLocation    *time.Location // nil uses time.Local
+	logZoneAuto    bool
+	detectedOffset *time.Location
+)
+
+func SetLogLocation(name string) error {
+	zoneMu.Lock()
+	defer zoneMu.Unlock()
+
+	logLocation = nil
+	logZoneAuto = false
+	detectedOffset = nil
+
+	switch name {
+	case "":
+		// default to local timezone of the client
+	case autoZone:
+		logZoneAuto = true
+	default:
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return err
+		}
+		logLocation = loc
+	}
+
+	return nil
+}
+
+func logZone() *time.Location {
+	zoneMu.RLock()
+	defer zoneMu.RUnlock()
+
+	switch true {
+	case logZoneAuto && detectedOffset != nil:
+		return detectedOffset
+	case logLocation != nil:
+		return logLocation
+	default:
+		return time.Local
+	}
+}
+
+func detectOffset(t time.Time, unix int) (time.Duration, error) {
+	// wall clock of the log timestamp read as if it were utc
+	_, off := t.In(logZone()).Zone()
+	wall := t.Add(time.Duration(off) * time.Second)
+
+	// difference to the vote unix time is the log timezone offset
+	d := wall.Sub(time.Unix(int64(unix), 0))
+	r := d.Round(zoneStep)
+	if r > maxZoneOffset || r < -maxZoneOffset {
+		return 0, errors.New("offset out of range")
+	}
+	if drift := d - r; drift > maxZoneDrift || drift < -maxZoneDrift {
+		return 0, errors.New("offset not aligned")
+	}
+
+	return r, nil
+}
+
+func setDetectedOffset(d time.Duration) bool {
+	zoneMu.Lock()
+	defer zoneMu.Unlock()
+
+	// only used when log timezone set to auto
+	if !logZoneAuto {
+		return false
+	}
+	if detectedOffset != nil {
+		if _, off := time.Unix(0, 0).In(detectedOffset).Zone(); time.Duration(off)*time.Second == d {
+			return false
+		}
+	}
+	detectedOffset = time.FixedZone("", int(d/time.Second))
+
+	return true
+}
+
+func zoneMismatch(t time.Time, unix int) bool {
+	// flag log timestamps that disagree with the vote unix time
+	d := t.Sub(time.Unix(int64(unix), 0))
+	return d > maxZoneDrift || d < -maxZoneDrift
+}
@@ -0,0 +1,115 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetLogLocation(t *testing.T) {
+	defer SetLogLocation("")
+
+	// test default local timezone
+	if err := SetLogLocation(""); err != nil {
+		t.Fatalf("data.SetLogLocation() returned error: %v", err)
+	}
+
+	if got := logZone(); got != time.Local {
+		t.Fatalf("data.logZone() returned: %v, wanted: %v", got, time.Local)
+	}
+
+	// test named timezone
+	if err := SetLogLocation("America/New_York"); err != nil {
+		t.Fatalf("data.SetLogLocation() returned error: %v", err)
+	}
+
+	if got := logZone().String(); got != "America/New_York" {
+		t.Fatalf("data.logZone() returned: %v, wanted: %v", got, "America/New_York")
+	}
+
+	// test unknown timezone
+	if err := SetLogLocation("Mars/Olympus_Mons"); err == nil {
+		t.Fatalf("data.SetLogLocation() returned: %v, wanted error", err)
+	}
+}
+
+func TestDetectOffset(t *testing.T) {
+	defer SetLogLocation("")
+	SetLogLocation("UTC")
+
+	// vote unix time 12:56:26 utc, log wall clock in eastern daylight time
+	vote := 1630155386
+	at := time.Unix(int64(vote), 0).UTC().Add(-4 * time.Hour)
+
+	// drifts well inside and well outside the allowed bound
+	tests := []struct {
+		drift time.Duration
+		ok    bool
+	}{
+		{0, true},
+		{30 * time.Second, true},
+		{-time.Minute, true},
+		{maxZoneDrift + 2*time.Minute, false},
+		{-(maxZoneDrift + 2*time.Minute), false},
+	}
+
+	for _, tc := range tests {
+		got, err := detectOffset(at.Add(tc.drift), vote)
+		if !tc.ok {
+			if err == nil {
+				t.Fatalf("data.detectOffset(%v) returned: %v, wanted error", tc.drift, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("data.detectOffset(%v) returned error: %v", tc.drift, err)
+		}
+		if want := -4 * time.Hour; got != want {
+			t.Fatalf("data.detectOffset(%v) returned: %v, wanted: %v", tc.drift, got, want)
+		}
+	}
+
+	// test offset out of range
+	if got, err := detectOffset(at, 0); err == nil {
+		t.Fatalf("data.detectOffset() returned: %v, wanted error", got)
+	}
+}
+
+func TestAutoLogLocation(t *testing.T) {
+	defer SetLogLocation("")
+	SetLogLocation(autoZone)
+
+	// sim bootstrap peers
+	setPeers(16, 16)
+
+	// test offset learned from vote and applied to created time
+	um := NewUMBroadcast()
+	if err := um.Parse(UMBroadcastedEx); err != nil {
+		t.Fatalf("data.UMBroadcastParse() returned error: %v", err)
+	}
+
+	want := time.Date(2021, 8, 28, 13, 0, 26, 0, time.UTC)
+	if !um.CreatedAt.Equal(want) || um.ZoneMismatch {
+		t.Fatalf("data.UMBroadcastParse() returned: %v, %v, wanted: %v, %v", um.CreatedAt, um.ZoneMismatch, want, false)
+	}
+
+	// test learned offset used for lines without unix time
+	got, _ := parseTime(P2PNumPeersEx)
+	want = time.Date(2021, 8, 28, 13, 10, 32, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Fatalf("data.parseTime() returned: %v, wanted: %v", got, want)
+	}
+}
+
+func TestZoneMismatch(t *testing.T) {
+	tt := time.Unix(1630155386, 0)
+
+	// test within allowed drift
+	if zoneMismatch(tt.Add(time.Minute), 1630155386) {
+		t.Fatalf("data.zoneMismatch() returned: %v, wanted: %v", true, false)
+	}
+
+	// test shifted by an hour, ie missed dst change
+	if !zoneMismatch(tt.Add(time.Hour), 1630155386) {
+		t.Fatalf("data.zoneMismatch() returned: %v, wanted: %v", false, true)
+	}
+}
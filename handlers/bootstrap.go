@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"os"
+	"strings"
+
+	"github.com/edgestats/edgestats-client/data"
+)
+
+const (
+	defaultBootstrapSize = 1024 * 1024 // bytes read from end of each log
+	maxHeldLines         = 1024
+)
+
+func Bootstrap(fp string) (bool, error) {
+	// scan current then rotated log backwards, newest lines first
+	for _, f := range []string{fp, rotatedPath(fp)} {
+		if f == "" {
+			break // not a rotated log name
+		}
+		lines, err := TailLines(f, defaultBootstrapSize)
+		if os.IsNotExist(err) && f != fp {
+			break
+		}
+		if err != nil {
+			return false, err
+		}
+
+		for i := len(lines) - 1; i >= 0; i-- {
+			data.SeedNode(lines[i])
+			if data.Bootstrapped() {
+				return true, nil
+			}
+		}
+	}
+
+	return data.Bootstrapped(), nil
+}
+
+func LogHistory(fp string) []string {
+	// existing logs oldest first, ie rotated then current
+	var fps []string
+	for _, f := range []string{rotatedPath(fp), fp} {
+		if f == "" {
+			continue
+		}
+		if _, err := os.Stat(f); err == nil {
+			fps = append(fps, f)
+		}
+	}
+	return fps
+}
+
+func rotatedPath(fp string) string {
+	// edge node rotates "log.log" to "log.old.log", empty if not a .log file
+	base := strings.TrimSuffix(fp, ".log")
+	if base == fp {
+		return ""
+	}
+	return base + ".old.log"
+}
+
+type holdBuffer struct {
+	lines   [][]byte
+	dropped int64
+}
+
+func (h *holdBuffer) hold(b []byte) {
+	// keep newest lines, oldest dropped when full
+	if len(h.lines) >= maxHeldLines {
+		h.lines = h.lines[1:]
+		h.dropped++
+	}
+	h.lines = append(h.lines, b)
+}
+
+func (h *holdBuffer) release() [][]byte {
+	lines := h.lines
+	h.lines = nil
+	return lines
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edgestats/edgestats-client/data"
+)
+
+func TestBootstrap(t *testing.T) {
+	// setup test variables
+	var lines = bytes.Split(bytes.TrimSpace(logs), []byte("\n"))
+	var tmp = t.TempDir()
+	var fp = filepath.Join(tmp, "log.log")
+
+	data.ResetNode()
+	defer data.ResetNode()
+
+	// test peers from current log, address from rotated log
+	_ = os.WriteFile(fp, lines[0], 0664)
+	_ = os.WriteFile(filepath.Join(tmp, "log.old.log"), lines[2], 0664)
+
+	ok, err := Bootstrap(fp)
+	if err != nil || !ok {
+		t.Fatalf("handlers.Bootstrap() returned: %v, %v, wanted: true", ok, err)
+	}
+
+	if addr, _, sp := data.NodeInfo(); addr != "0x8d25fa2e7d" || sp != 16 {
+		t.Fatalf("handlers.Bootstrap() seeded: %v, %v", addr, sp)
+	}
+
+	// test no rotated log, not bootstrapped
+	data.ResetNode()
+	_ = os.Remove(filepath.Join(tmp, "log.old.log"))
+
+	if ok, err = Bootstrap(fp); err != nil || ok {
+		t.Fatalf("handlers.Bootstrap() returned: %v, %v, wanted: false", ok, err)
+	}
+
+	// test none file path
+	if _, err = Bootstrap(filepath.Join(tmp, "none.log")); err == nil {
+		t.Fatalf("handlers.Bootstrap() returned: nil, wanted error")
+	}
+}
+
+func TestLogHistory(t *testing.T) {
+	// setup test variables
+	var tmp = t.TempDir()
+	var fp = filepath.Join(tmp, "log.log")
+
+	// test current log only
+	_ = os.WriteFile(fp, logs, 0664)
+	if got := LogHistory(fp); len(got) != 1 || got[0] != fp {
+		t.Fatalf("handlers.LogHistory() returned: %v", got)
+	}
+
+	// test rotated log first
+	_ = os.WriteFile(filepath.Join(tmp, "log.old.log"), logs, 0664)
+	if got := LogHistory(fp); len(got) != 2 || got[0] != filepath.Join(tmp, "log.old.log") {
+		t.Fatalf("handlers.LogHistory() returned: %v", got)
+	}
+}
+
+func TestRotatedPath(t *testing.T) {
+	// test .log names rotated, others skipped without panic
+	tests := map[string]string{
+		"/logs/log.log": "/logs/log.old.log",
+		"node.log":      "node.old.log",
+		"log.txt":       "",
+		"lg":            "",
+		"":              "",
+	}
+	for fp, want := range tests {
+		if got := rotatedPath(fp); got != want {
+			t.Fatalf("handlers.rotatedPath(%q) returned: %q, wanted: %q", fp, got, want)
+		}
+	}
+
+	// test history of other names is the file itself
+	fp := writeTempLog(t, logs)
+	txt := fp + ".txt"
+	_ = os.Rename(fp, txt)
+	if got := LogHistory(txt); len(got) != 1 || got[0] != txt {
+		t.Fatalf("handlers.LogHistory() returned: %v", got)
+	}
+}
+
+func TestHoldBuffer(t *testing.T) {
+	var h holdBuffer
+
+	// test oldest lines dropped when full
+	for i := 0; i < maxHeldLines+2; i++ {
+		h.hold([]byte{byte(i)})
+	}
+	if len(h.lines) != maxHeldLines || h.dropped != 2 || h.lines[0][0] != 2 {
+		t.Fatalf("handlers.holdBuffer.hold() kept: %v, dropped: %v", len(h.lines), h.dropped)
+	}
+
+	// test release empties buffer
+	if got := h.release(); len(got) != maxHeldLines || len(h.lines) != 0 {
+		t.Fatalf("handlers.holdBuffer.release() returned: %v", len(got))
+	}
+}
+
+func TestPipelineHoldsBeforeBootstrap(t *testing.T) {
+	// setup test variables
+	var p = NewPipeline(4)
+	var lines = bytes.Split(bytes.TrimSpace(logs), []byte("\n"))
+	var done = make(chan struct{})
+
+	data.ResetNode()
+	defer data.ResetNode()
+
+	go func() {
+		p.Run(context.Background())
+		close(done)
+	}()
+
+	// test peers line held until vote sets address
+	p.Emit(lines[0])
+	p.Emit(lines[2])
+	p.Close()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("handlers.Pipeline.Run() did not return after close")
+	}
+
+	parser := p.Stats()[1]
+	if parser.In != 2 || parser.Out != 2 || parser.Dropped != 0 || parser.Held != 0 {
+		t.Fatalf("handlers.Pipeline.Stats() returned parser: %+v, wanted out: %v", parser, 2)
+	}
+}
+
+func TestPipelineDropsHeldOnClose(t *testing.T) {
+	// setup test variables
+	var p = NewPipeline(4)
+	var lines = bytes.Split(bytes.TrimSpace(logs), []byte("\n"))
+	var done = make(chan struct{})
+
+	data.ResetNode()
+	defer data.ResetNode()
+
+	go func() {
+		p.Run(context.Background())
+		close(done)
+	}()
+
+	// test peers line never bootstrapped counted as dropped
+	p.Emit(lines[0])
+	p.Close()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("handlers.Pipeline.Run() did not return after close")
+	}
+
+	parser := p.Stats()[1]
+	if parser.In != 1 || parser.Out != 0 || parser.Dropped != 1 || parser.Held != 0 {
+		t.Fatalf("handlers.Pipeline.Stats() returned parser: %+v, wanted dropped: %v", parser, 1)
+	}
+}
+
+func TestPipelineDropsHeldAfterTimeout(t *testing.T) {
+	// setup test variables
+	var p = NewPipeline(4)
+	var lines = bytes.Split(bytes.TrimSpace(logs), []byte("\n"))
+	var ctx, cancel = context.WithCancel(context.Background())
+	var done = make(chan struct{})
+
+	p.holdFor = 10 * time.Millisecond
+	data.ResetNode()
+	defer data.ResetNode()
+
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// test held line dropped while pipeline still open
+	p.Emit(lines[0])
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		parser := p.Stats()[1]
+		if parser.In == 1 && parser.Dropped == 1 && parser.Held == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("handlers.Pipeline.Stats() returned parser: %+v, wanted dropped: %v", parser, 1)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
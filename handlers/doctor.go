@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/edgestats/edgestats-client/data"
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	CheckPass = "PASS"
+	CheckWarn = "WARN"
+	CheckFail = "FAIL"
+
+	defaultTailSize = 256 * 1024 // bytes read from end of log
+)
+
+type Check struct {
+	Name   string
+	Status string
+	Detail string
+	Hint   string // what to do when not passing
+}
+
+func Diagnose(fp string, wait time.Duration) []Check {
+	var checks []Check
+
+	// log path exists, else nothing else can pass
+	info, err := os.Stat(fp)
+	if err != nil {
+		return append(checks, Check{"log file", CheckFail, err.Error(),
+			"start the edge node once or set LOG_FILEPATH to its log.log"})
+	}
+	checks = append(checks, Check{"log file", CheckPass, fp, ""})
+
+	checks = append(checks, checkWrites(fp, info.Size(), wait)...)
+	lines, err := TailLines(fp, defaultTailSize)
+	if err != nil {
+		return append(checks, Check{"recent lines", CheckFail, err.Error(), "check read permission on the log file"})
+	}
+	checks = append(checks, checkFilter(lines))
+	checks = append(checks, checkBootstrap(fp))
+
+	return checks
+}
+
+func TailLines(fp string, max int64) ([][]byte, error) {
+	f, err := os.Open(fp)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// read last max bytes, drop partial first line
+	offset := info.Size() - max
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			b = b[i+1:]
+		}
+	}
+
+	var lines [][]byte
+	for _, l := range bytes.Split(b, []byte("\n")) {
+		if len(bytes.TrimSpace(l)) > 0 {
+			lines = append(lines, l)
+		}
+	}
+
+	return lines, nil
+}
+
+func checkWrites(fp string, size int64, wait time.Duration) []Check {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return []Check{{"file events", CheckFail, err.Error(), "raise the open file or inotify watch limits"}}
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(fp); err != nil {
+		return []Check{{"file events", CheckFail, err.Error(), "raise the open file or inotify watch limits"}}
+	}
+
+	// wait for a write, poke as run does
+	var fired bool
+	timeout := time.After(wait)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+loop:
+	for {
+		select {
+		case e := <-watcher.Events:
+			if e.Op&fsnotify.Write == fsnotify.Write {
+				fired = true
+				break loop
+			}
+		case <-ticker.C:
+			PokeFilePath(fp)
+		case <-timeout:
+			break loop
+		}
+	}
+
+	info, err := os.Stat(fp)
+	if err != nil {
+		return []Check{{"log growing", CheckFail, err.Error(), "the log was removed, check the edge node"}}
+	}
+	grew := info.Size() > size
+
+	switch true {
+	case grew && fired:
+		return []Check{
+			{"log growing", CheckPass, fmt.Sprintf("%d bytes written", info.Size()-size), ""},
+			{"file events", CheckPass, "write events received", ""},
+		}
+	case grew:
+		return []Check{
+			{"log growing", CheckPass, fmt.Sprintf("%d bytes written", info.Size()-size), ""},
+			{"file events", CheckFail, "log grew but no write event fired",
+				"file events do not work on this path, ie network or synced folders, move the log to a local disk"},
+		}
+	case fired:
+		return []Check{
+			{"log growing", CheckWarn, "write event without size change", "the log may have been rotated, run doctor again"},
+			{"file events", CheckPass, "write events received", ""},
+		}
+	default:
+		return []Check{
+			{"log growing", CheckWarn, fmt.Sprintf("no writes in %v", wait),
+				"check the edge node is running, or wait longer with --wait"},
+			{"file events", CheckWarn, "not checked, log not written", ""},
+		}
+	}
+}
+
+func checkFilter(lines [][]byte) Check {
+	counts := make(map[int]int)
+	for _, b := range lines {
+		counts[data.Filter(b)]++
+	}
+
+	detail := fmt.Sprintf("%d lines, uptime: %d, p2p: %d, netsync: %d, consensus: %d, incident: %d",
+		len(lines), counts[data.UMFilter], counts[data.P2PFilter], counts[data.NSFilter], counts[data.CSFilter], counts[data.LevelFilter])
+
+	switch true {
+	case len(lines) == counts[data.ErrFilter]:
+		return Check{"recent lines", CheckFail, detail, "no edge node lines found, check LOG_FILEPATH points at the edge node log.log"}
+	case counts[data.UMFilter] == 0:
+		return Check{"recent lines", CheckWarn, detail, "no [uptime miner] lines yet, the node may still be syncing"}
+	default:
+		return Check{"recent lines", CheckPass, detail, ""}
+	}
+}
+
+func checkBootstrap(fp string) Check {
+	// same as startup, current then rotated log
+	if _, err := Bootstrap(fp); err != nil {
+		return Check{"bootstrap", CheckFail, err.Error(), "check read permission on the log file"}
+	}
+
+	addr, np, sp := data.NodeInfo()
+	detail := fmt.Sprintf("address: %q, peers: %d/%d", addr, np, sp)
+
+	switch true {
+	case addr == "":
+		return Check{"bootstrap", CheckFail, detail, "no Broadcasted vote found in recent or rotated log, wait for the node to vote then run doctor again"}
+	case sp == 0:
+		return Check{"bootstrap", CheckFail, detail, "no numPeers line found in recent or rotated log, wait for the node to connect to peers"}
+	default:
+		return Check{"bootstrap", CheckPass, detail, ""}
+	}
+}
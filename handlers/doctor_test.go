@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestTailLines(t *testing.T) {
+	fp := writeTempLog(t, []byte("first line\nsecond line\n\nthird line\n"))
+
+	// test whole file when smaller than max
+	got, err := TailLines(fp, 1024)
+	if err != nil {
+		t.Fatalf("handlers.TailLines() returned error: %v", err)
+	}
+	if len(got) != 3 || string(got[0]) != "first line" {
+		t.Fatalf("handlers.TailLines() returned: %q", got)
+	}
+
+	// test partial first line dropped
+	got, _ = TailLines(fp, 20)
+	if len(got) != 1 || string(got[0]) != "third line" {
+		t.Fatalf("handlers.TailLines() returned: %q, wanted: %q", got, "third line")
+	}
+
+	// test none file path
+	if _, err = TailLines(fp+".none", 1024); err == nil {
+		t.Fatalf("handlers.TailLines() returned: nil, wanted error")
+	}
+}
+
+func TestCheckFilter(t *testing.T) {
+	lines := bytes.Split(bytes.TrimSpace(logs), []byte("\n"))
+
+	// test recent edge node lines pass
+	if got := checkFilter(lines); got.Status != CheckPass {
+		t.Fatalf("handlers.checkFilter() returned: %+v, wanted: %v", got, CheckPass)
+	}
+
+	// test unrelated lines fail
+	if got := checkFilter([][]byte{[]byte("no match log")}); got.Status != CheckFail {
+		t.Fatalf("handlers.checkFilter() returned: %+v, wanted: %v", got, CheckFail)
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	fp := writeTempLog(t, logs)
+
+	// test log written while waiting
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		f, _ := os.OpenFile(fp, os.O_APPEND|os.O_WRONLY, 0664)
+		f.Write(logs)
+		f.Close()
+	}()
+
+	got := Diagnose(fp, 3*time.Second)
+	for _, c := range got {
+		if c.Status != CheckPass {
+			t.Fatalf("handlers.Diagnose() returned: %+v, wanted all: %v", got, CheckPass)
+		}
+	}
+
+	// test none file path
+	got = Diagnose(fp+".none", time.Millisecond)
+	if len(got) != 1 || got[0].Status != CheckFail {
+		t.Fatalf("handlers.Diagnose() returned: %+v, wanted: %v", got, CheckFail)
+	}
+}
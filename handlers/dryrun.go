@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/edgestats/edgestats-client/data"
+)
+
+func (p *Pipeline) SetDryRun(w io.Writer) {
+	// print records instead of sending, set before run
+	p.dry = w
+}
+
+func PrintResult(w io.Writer, r LineResult) {
+	name := data.FilterName(r.Filter)
+
+	switch true {
+	case r.Err == nil:
+		fmt.Fprintf(w, "line %d [%s] POST %s\n", r.Line, name, r.Job.URL)
+	case data.IsThrottled(r.Err):
+		fmt.Fprintf(w, "line %d [%s] sampled out, not sent\n", r.Line, name)
+	default:
+		fmt.Fprintf(w, "line %d [%s] parse error: %v\n", r.Line, name, r.Err)
+	}
+	fmt.Fprintf(w, "  < %s\n", bytes.TrimSpace([]byte(r.Text)))
+
+	if r.Err != nil {
+		return
+	}
+
+	// indent body as sent, fall back to raw json
+	var out bytes.Buffer
+	if err := json.Indent(&out, r.Job.Body, "    ", "  "); err != nil {
+		fmt.Fprintf(w, "  > %s\n", r.Job.Body)
+		return
+	}
+	fmt.Fprintf(w, "  > %s\n", out.String())
+}
+
+func checkLine(n int, b []byte) []LineResult {
+	// filter and parse line, job built without reserving record
+	fs := data.Filters(b)
+
+	var rs []LineResult
+	for _, i := range fs {
+		r := LineResult{Line: n, Text: string(b), Filter: i}
+		p := data.NewParser(i)
+		if err := p.Parse(b); err != nil {
+			// warning without category record, ie incident only
+			if data.IsNoMatch(err) && len(fs) > 1 {
+				continue
+			}
+			r.Err = err
+			rs = append(rs, r)
+			continue
+		}
+		r.Job, r.Err = data.Preview(p)
+		rs = append(rs, r)
+	}
+
+	return rs
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgestats/edgestats-client/data"
+)
+
+func TestPrintResult(t *testing.T) {
+	// setup test variables
+	var buf bytes.Buffer
+	var r = LineResult{
+		Line:   3,
+		Text:   "... [p2p] ...",
+		Filter: data.P2PFilter,
+		Job:    data.Job{URL: "http://127.0.0.1:8000/stats/uptimes/peers", Body: []byte(`{"num_peers":16}`)},
+	}
+
+	// test record printed with url, source and indented body
+	PrintResult(&buf, r)
+	got := buf.String()
+	for _, want := range []string{"line 3 [p2p] POST http://127.0.0.1:8000/stats/uptimes/peers", "  < ... [p2p] ...", `"num_peers": 16`} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("handlers.PrintResult() returned: %q, wanted: %q", got, want)
+		}
+	}
+
+	// test parse error printed without body
+	buf.Reset()
+	r.Err = errors.New("no address bootstrapped")
+	PrintResult(&buf, r)
+	got = buf.String()
+	if !strings.Contains(got, "parse error: no address bootstrapped") || strings.Contains(got, "  > ") {
+		t.Fatalf("handlers.PrintResult() returned: %q", got)
+	}
+}
+
+func TestPipelineDryRun(t *testing.T) {
+	// setup test variables
+	var buf bytes.Buffer
+	var p = NewPipeline(1)
+	var lines = bytes.Split(bytes.TrimSpace(logs), []byte("\n"))
+	var done = make(chan struct{})
+
+	p.SetDryRun(&buf)
+	go func() {
+		p.Run(context.Background())
+		close(done)
+	}()
+
+	// test lines printed, nothing reaches sender
+	for _, b := range lines {
+		if err := p.Emit(b); err != nil {
+			t.Fatalf("handlers.Pipeline.Emit() returned error: %v", err)
+		}
+	}
+	p.Emit([]byte("no match log"))
+	p.Close()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("handlers.Pipeline.Run() did not return after close")
+	}
+
+	stats := p.Stats()
+	if stats[1].Out != 3 || stats[1].Dropped != 1 || stats[3].In != 0 {
+		t.Fatalf("handlers.Pipeline.Stats() returned: %+v, wanted 3 printed", stats)
+	}
+
+	if got := strings.Count(buf.String(), "  < "); got != 3 {
+		t.Fatalf("handlers.Pipeline dry run printed %d lines, wanted: %d", got, 3)
+	}
+}
@@ -12,9 +12,18 @@ import (
 	"github.com/fsnotify/fsnotify"
 )
 
+var (
+	errNoFilter = errors.New("no filter match")
+)
+
 func ProcessEvent(watcher *fsnotify.Watcher, event fsnotify.Event, fp string, offset int64) (int64, error) {
 	var err error
 
+	// writes and log rotation both show the node is logging
+	if dog != nil && event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+		dog.MarkWrite()
+	}
+
 	if event.Op&fsnotify.Write == fsnotify.Write {
 		offset, err = processLog(fp, offset)
 		if err != nil {
@@ -27,10 +36,11 @@ func ProcessEvent(watcher *fsnotify.Watcher, event fsnotify.Event, fp string, of
 		time.Sleep(1000 * time.Millisecond)
 
 		// scan writes to new "log.old.log", ie old "log.log"
-		fpOld := fp[:len(fp)-3] + "old.log"
-		offset, err = processLog(fpOld, offset)
-		if err != nil {
-			return offset, err
+		if fpOld := rotatedPath(fp); fpOld != "" {
+			offset, err = processLog(fpOld, offset)
+			if err != nil {
+				return offset, err
+			}
 		}
 
 		// stop watching new "log.old.log", ie old "log.log"
@@ -96,25 +106,34 @@ func processLog(fp string, offset int64) (int64, error) {
 		// filter log entries
 		lines++
 		b := scanner.Bytes()
-		i := data.Filter(b)
 
-		switch i {
-		case data.UMFilter:
-			// process UM log type
-			matches++
-			p := data.NewUMBroadcast()
-			if err := data.SendData(p, b); err != nil {
-				continue // perhaps log to log file
+		// track uptime miner activity for liveness
+		if dog != nil && data.Filter(b) == data.UMFilter {
+			t, _ := data.LineTime(b)
+			dog.MarkVote(t)
+		}
+
+		// hand off to pipeline if running
+		if pipe != nil {
+			if err := pipe.Emit(b); err != nil {
+				return offset, err
 			}
-		case data.P2PFilter:
-			// process P2P log type
-			matches++
-			p := data.NewP2PNumPeers()
+			continue
+		}
+
+		fs := data.Filters(b)
+		if len(fs) == 0 { //filter.ErrFilter
+			misses++
+			continue
+		}
+
+		// process uptime, p2p, netsync, consensus and warn/error log types
+		matches++
+		for _, i := range fs {
+			p := data.NewParser(i)
 			if err := data.SendData(p, b); err != nil {
 				continue // perhaps log to log file
 			}
-		default: //filter.ErrFilter
-			misses++
 		}
 	}
 
@@ -130,6 +149,13 @@ func processLog(fp string, offset int64) (int64, error) {
 	return offset, nil
 }
 
+func ReplayLog(fp string, offset int64) (int64, error) {
+	// process file once from offset, ie backfill after downtime
+	// older heights than already sent are expected, not suspicious
+	data.ResetHeights()
+	return processLog(fp, offset)
+}
+
 func getOffset(f *os.File, offset int64) (int64, int64, error) {
 	var size int64
 
@@ -243,3 +243,9 @@ func TestPokeFilePath(t *testing.T) {
 		t.Fatalf("handlers.PokeFilePath() returned: %v, wanted error: %v", nil, err)
 	}
 }
+
+func writeTempLog(t *testing.T, buf []byte) string {
+	fp := filepath.Join(t.TempDir(), "log.log")
+	_ = os.WriteFile(fp, buf, 0664)
+	return fp
+}
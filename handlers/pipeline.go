@@ -0,0 +1,354 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/edgestats/edgestats-client/data"
+)
+
+const (
+	defaultStageSize   = 256
+	defaultHoldTimeout = 5 * time.Minute // held lines dropped if not bootstrapped by then
+)
+
+var (
+	pipe              *Pipeline // nil processes lines inline
+	errPipelineClosed = errors.New("pipeline closed")
+)
+
+type StageStats struct {
+	Name    string `json:"name"`
+	In      int64  `json:"in"`
+	Out     int64  `json:"out"`
+	Dropped int64  `json:"dropped"`
+	Errors  int64  `json:"errors"`
+	Queued  int    `json:"queued"`
+	Held    int64  `json:"held,omitempty"`
+}
+
+type stage struct {
+	in      int64
+	out     int64
+	dropped int64
+	errors  int64
+	held    int64
+}
+
+type Pipeline struct {
+	mu       sync.RWMutex
+	closed   bool
+	quit     chan struct{}
+	quitOnce sync.Once
+	lines    chan []byte      // tailer to parser
+	records  chan data.Parser // parser to enricher
+	jobs     chan data.Job    // enricher to sender
+	dry      io.Writer        // prints parsed lines, nothing sent
+	pending  holdBuffer       // lines parsed before bootstrap, parser only
+	holdFor  time.Duration    // held lines dropped after, ie node never bootstraps
+	tailer   stage
+	parser   stage
+	enricher stage
+	sender   stage
+}
+
+func NewPipeline(size int) *Pipeline {
+	if size <= 0 {
+		size = defaultStageSize
+	}
+
+	return &Pipeline{
+		quit:    make(chan struct{}),
+		holdFor: defaultHoldTimeout,
+		lines:   make(chan []byte, size),
+		records: make(chan data.Parser, size),
+		jobs:    make(chan data.Job, size),
+	}
+}
+
+func SetPipeline(p *Pipeline) {
+	pipe = p
+}
+
+func (p *Pipeline) Emit(b []byte) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return errPipelineClosed
+	}
+	select {
+	case <-p.quit:
+		return errPipelineClosed
+	default:
+	}
+	atomic.AddInt64(&p.tailer.in, 1)
+
+	// copy line, scanner reuses its buffer
+	c := make([]byte, len(b))
+	copy(c, b)
+
+	// block when parser behind, ie backpressure to the reader
+	select {
+	case p.lines <- c:
+		atomic.AddInt64(&p.tailer.out, 1)
+		return nil
+	case <-p.quit:
+		atomic.AddInt64(&p.tailer.dropped, 1)
+		return errPipelineClosed
+	}
+}
+
+func (p *Pipeline) Close() {
+	// unblock pending emits then let stages drain
+	p.quitOnce.Do(func() { close(p.quit) })
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.closed {
+		p.closed = true
+		close(p.lines)
+	}
+}
+
+func (p *Pipeline) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(3)
+
+	// unblock pending emits on cancel
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			p.quitOnce.Do(func() { close(p.quit) })
+		case <-stop:
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		defer close(p.records)
+		p.parse(ctx)
+	}()
+
+	go func() {
+		defer wg.Done()
+		defer close(p.jobs)
+		p.enrich(ctx)
+	}()
+
+	go func() {
+		defer wg.Done()
+		p.send(ctx)
+	}()
+
+	wg.Wait()
+}
+
+func (p *Pipeline) parse(ctx context.Context) {
+	// later stages end once their input closes
+	defer p.dropHeld()
+
+	// fires once per hold, ie from first line held until bootstrapped
+	var expire <-chan time.Time
+
+	for {
+		// prefer cancel over pending lines
+		if ctx.Err() != nil {
+			return
+		}
+
+		var b []byte
+		var ok bool
+		select {
+		case b, ok = <-p.lines:
+			if !ok {
+				return
+			}
+		case <-expire:
+			fmt.Printf("Warning: node not bootstrapped after %v, held lines dropped\n", p.holdFor)
+			p.dropHeld()
+			expire = nil
+			continue
+		case <-ctx.Done():
+			return
+		}
+		n := atomic.AddInt64(&p.parser.in, 1)
+
+		// dry run prints in place of enrich and send
+		if p.dry != nil {
+			rs := checkLine(int(n), b)
+			for _, r := range rs {
+				PrintResult(p.dry, r)
+			}
+			if len(rs) > 0 {
+				atomic.AddInt64(&p.parser.out, 1)
+			} else {
+				atomic.AddInt64(&p.parser.dropped, 1)
+			}
+			continue
+		}
+
+		rs, err := parseLine(b)
+		if data.IsNotBootstrapped(err) {
+			if expire == nil {
+				expire = time.After(p.holdFor)
+			}
+			p.hold(b)
+			continue
+		}
+		if err != nil {
+			atomic.AddInt64(&p.parser.dropped, 1)
+			continue
+		}
+
+		// bootstrapped, held lines first to keep order
+		expire = nil
+		for _, h := range p.pending.release() {
+			hrs, err := parseLine(h)
+			if err != nil {
+				atomic.AddInt64(&p.parser.dropped, 1)
+				continue
+			}
+			for _, hr := range hrs {
+				if !p.forward(ctx, hr) {
+					return
+				}
+			}
+		}
+		atomic.StoreInt64(&p.parser.held, 0)
+
+		for _, r := range rs {
+			if !p.forward(ctx, r) {
+				return
+			}
+		}
+	}
+}
+
+func (p *Pipeline) hold(b []byte) {
+	// lines already copied by emit
+	n := p.pending.dropped
+	p.pending.hold(b)
+	atomic.AddInt64(&p.parser.dropped, p.pending.dropped-n)
+	atomic.StoreInt64(&p.parser.held, int64(len(p.pending.lines)))
+}
+
+func (p *Pipeline) dropHeld() {
+	// never bootstrapped, held lines are not sent
+	n := len(p.pending.release())
+	atomic.AddInt64(&p.parser.dropped, int64(n))
+	atomic.StoreInt64(&p.parser.held, 0)
+}
+
+func (p *Pipeline) forward(ctx context.Context, r data.Parser) bool {
+	select {
+	case p.records <- r:
+		atomic.AddInt64(&p.parser.out, 1)
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (p *Pipeline) enrich(ctx context.Context) {
+	for r := range p.records {
+		atomic.AddInt64(&p.enricher.in, 1)
+
+		j, err := data.Encode(r)
+		if data.IsDuplicate(err) || data.IsQuarantined(err) || data.IsThrottled(err) {
+			atomic.AddInt64(&p.enricher.dropped, 1)
+			continue
+		}
+		if err != nil {
+			atomic.AddInt64(&p.enricher.errors, 1)
+			continue
+		}
+
+		select {
+		case p.jobs <- j:
+			atomic.AddInt64(&p.enricher.out, 1)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *Pipeline) send(ctx context.Context) {
+	for j := range p.jobs {
+		atomic.AddInt64(&p.sender.in, 1)
+
+		if err := data.Dispatch(ctx, j); err != nil {
+			atomic.AddInt64(&p.sender.errors, 1)
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+		atomic.AddInt64(&p.sender.out, 1)
+	}
+}
+
+func (p *Pipeline) Stats() []StageStats {
+	return []StageStats{
+		p.tailer.stats("tailer", len(p.lines)),
+		p.parser.stats("parser", len(p.records)),
+		p.enricher.stats("enricher", len(p.jobs)),
+		p.sender.stats("sender", data.QueueLen()),
+	}
+}
+
+func (p *Pipeline) Depth() int {
+	return len(p.lines) + len(p.records) + len(p.jobs)
+}
+
+func (s *stage) stats(name string, queued int) StageStats {
+	return StageStats{
+		Name:    name,
+		In:      atomic.LoadInt64(&s.in),
+		Out:     atomic.LoadInt64(&s.out),
+		Dropped: atomic.LoadInt64(&s.dropped),
+		Errors:  atomic.LoadInt64(&s.errors),
+		Queued:  queued,
+		Held:    atomic.LoadInt64(&s.held),
+	}
+}
+
+func parseLine(b []byte) ([]data.Parser, error) {
+	// filter and parse log entry by category, warnings and errors also as incident
+	fs := data.Filters(b)
+	if len(fs) == 0 { //filter.ErrFilter
+		return nil, errNoFilter
+	}
+
+	var rs []data.Parser
+	var first error
+	for _, i := range fs {
+		p := data.NewParser(i)
+		err := p.Parse(b)
+		if data.IsNotBootstrapped(err) {
+			// hold whole line, parsed again once bootstrapped
+			return nil, err
+		}
+		if err != nil {
+			data.QuarantineLine(p, b, err)
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+		rs = append(rs, p)
+	}
+	if len(rs) == 0 {
+		return nil, first
+	}
+
+	return rs, nil
+}
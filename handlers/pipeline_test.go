@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edgestats/edgestats-client/data"
+)
+
+func TestPipelineRun(t *testing.T) {
+	// setup test variables
+	var p = NewPipeline(1)
+	var lines = bytes.Split(bytes.TrimSpace(logs), []byte("\n"))
+	var done = make(chan struct{})
+
+	go func() {
+		p.Run(context.Background())
+		close(done)
+	}()
+
+	// test lines flow through stages then drain on close
+	for _, b := range lines {
+		if err := p.Emit(b); err != nil {
+			t.Fatalf("handlers.Pipeline.Emit() returned error: %v", err)
+		}
+	}
+	if err := p.Emit(bytes.TrimSpace(lines[0])); err != nil {
+		t.Fatalf("handlers.Pipeline.Emit() returned error: %v", err)
+	}
+	p.Close()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("handlers.Pipeline.Run() did not return after close")
+	}
+
+	stats := p.Stats()
+	tailer, parser := stats[0], stats[1]
+	if tailer.In != 4 || tailer.Out != 4 {
+		t.Fatalf("handlers.Pipeline.Stats() returned tailer: %+v, wanted in/out: %v", tailer, 4)
+	}
+
+	if parser.In != 4 || parser.Out+parser.Dropped != 4 {
+		t.Fatalf("handlers.Pipeline.Stats() returned parser: %+v, wanted in: %v", parser, 4)
+	}
+
+	// test emit after close
+	if err := p.Emit(lines[0]); err != errPipelineClosed {
+		t.Fatalf("handlers.Pipeline.Emit() returned: %v, wanted: %v", err, errPipelineClosed)
+	}
+}
+
+func TestPipelineCancel(t *testing.T) {
+	// setup test variables
+	var p = NewPipeline(1)
+	var ctx, cancel = context.WithCancel(context.Background())
+	var done = make(chan error)
+
+	// fill lines channel with no stages running
+	p.Emit([]byte("a"))
+	go func() {
+		done <- p.Emit([]byte("b"))
+	}()
+
+	// test blocked emit released on cancel
+	cancel()
+	p.Run(ctx)
+
+	select {
+	case err := <-done:
+		if err != errPipelineClosed {
+			t.Fatalf("handlers.Pipeline.Emit() returned: %v, wanted: %v", err, errPipelineClosed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("handlers.Pipeline.Emit() still blocked after cancel")
+	}
+}
+
+func TestProcessLogPipeline(t *testing.T) {
+	// setup test variables
+	var p = NewPipeline(8)
+
+	SetPipeline(p)
+	defer SetPipeline(nil)
+
+	// create tmp file with data
+	tmp := t.TempDir()
+	fp := filepath.Join(tmp, "log.log")
+	_ = os.WriteFile(fp, logs, 0664)
+
+	// test lines handed to pipeline rather than sent inline
+	if _, err := processLog(fp, 0); err != nil {
+		t.Fatalf("handlers.processLog() returned error: %v", err)
+	}
+
+	if got := p.Stats()[0].In; got != 4 {
+		t.Fatalf("handlers.Pipeline.Stats() returned tailer in: %v, wanted: %v", got, 4)
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	// test unmatched line
+	if p, err := parseLine([]byte("... [unmatched] ...")); err != errNoFilter {
+		t.Fatalf("handlers.parseLine() returned: %v, %v, wanted error: %v", p, err, errNoFilter)
+	}
+}
+
+func TestParseLineWarning(t *testing.T) {
+	// setup test variables
+	var lines = bytes.Split(bytes.TrimSpace(logs), []byte("\n"))
+
+	data.ResetNode()
+	defer data.ResetNode()
+	data.SeedNode(bytes.TrimSpace(lines[0]))
+	data.SeedNode(bytes.TrimSpace(lines[2]))
+
+	// test warning in category without record sent as incident
+	b := []byte("[2021-09-29T20:55:02Z] [info] [ThetaEdgeLauncher] [2021-09-29T20:55:02Z]  ERRO [uptime miner] Failed to broadcast vote")
+	rs, err := parseLine(b)
+	if err != nil || len(rs) != 1 {
+		t.Fatalf("handlers.parseLine() returned: %v, %v, wanted one record", rs, err)
+	}
+
+	if _, ok := rs[0].(*data.Incident); !ok {
+		t.Fatalf("handlers.parseLine() returned: %T, wanted: %T", rs[0], &data.Incident{})
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultStatusAddr = "127.0.0.1:8765"
+	statusPath        = "/status"
+)
+
+var (
+	statusMu        sync.RWMutex
+	statusProviders = map[string]func() interface{}{}
+)
+
+func RegisterStatus(name string, fn func() interface{}) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+
+	statusProviders[name] = fn
+}
+
+func GetStatusAddr() string {
+	// "off" disables the local status api
+	addr := os.Getenv("STATUS_ADDR")
+	if addr == "" {
+		addr = defaultStatusAddr
+	}
+	return addr
+}
+
+func StatusHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(statusPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(snapshotStatus())
+	})
+
+	return mux
+}
+
+func ServeStatus(ctx context.Context, addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{Handler: StatusHandler(), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	if err := srv.Serve(ln); err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
+
+func snapshotStatus() map[string]interface{} {
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+
+	s := make(map[string]interface{}, len(statusProviders))
+	for name, fn := range statusProviders {
+		s[name] = fn()
+	}
+
+	return s
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatusHandler(t *testing.T) {
+	RegisterStatus("test", func() interface{} {
+		return map[string]int{"queued": 3}
+	})
+
+	srv := httptest.NewServer(StatusHandler())
+	defer srv.Close()
+
+	// test providers rendered as json
+	resp, err := http.Get(srv.URL + statusPath)
+	if err != nil {
+		t.Fatalf("handlers.StatusHandler() returned error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var got map[string]map[string]int
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("handlers.StatusHandler() returned error: %v", err)
+	}
+
+	if got["test"]["queued"] != 3 {
+		t.Fatalf("handlers.StatusHandler() returned: %v", got)
+	}
+
+	// test method not allowed
+	resp, _ = http.Post(srv.URL+statusPath, "application/json", nil)
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("handlers.StatusHandler() returned: %v, wanted: %v", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"github.com/edgestats/edgestats-client/data"
+)
+
+type RetryStats struct {
+	Lines     int `json:"lines"`
+	Fixed     int `json:"fixed"`
+	Unmatched int `json:"unmatched"`
+	Failed    int `json:"failed"`
+}
+
+func RetryUnparsed(fp string, send bool, fn func(LineResult)) (RetryStats, error) {
+	var rs RetryStats
+
+	ls, err := data.ReadUnparsed(fp)
+	if err != nil {
+		return rs, err
+	}
+
+	// re-run quarantined lines with current parsers
+	var kept []data.UnparsedLine
+	for n, l := range ls {
+		rs.Lines++
+		b := []byte(l.Line)
+
+		i := data.Filter(b)
+		p := data.NewParser(i)
+		if p == nil {
+			rs.Unmatched++ // filter no longer matches, nothing to send
+			continue
+		}
+
+		r := LineResult{Line: n + 1, Text: l.Line, Filter: i}
+		r.Err = p.Parse(b)
+
+		// keep lines still failing, or not yet sendable
+		keep := data.IsParseFailure(r.Err) || data.IsNotBootstrapped(r.Err)
+		if r.Err == nil {
+			if send {
+				// delivered inline, kept unless server accepted or knew it
+				r.Err = data.Deliver(p)
+				keep = r.Err != nil && !data.IsDuplicate(r.Err) && !data.IsQuarantined(r.Err)
+			} else {
+				r.Job, r.Err = data.Preview(p)
+			}
+		}
+
+		if keep {
+			rs.Failed++
+			l.Error = r.Err.Error()
+			kept = append(kept, l)
+		} else {
+			rs.Fixed++
+		}
+
+		if fn != nil {
+			fn(r)
+		}
+	}
+
+	// dry run leaves the file as is
+	if !send || len(ls) == 0 {
+		return rs, nil
+	}
+
+	return rs, data.WriteUnparsed(fp, kept)
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edgestats/edgestats-client/data"
+)
+
+func TestRetryUnparsed(t *testing.T) {
+	// setup test variables
+	var fp = filepath.Join(t.TempDir(), "unparsed.log")
+	var broken = "[2021-08-28 09:00:26.951] [info] [ThetaEdgeLauncher] [2021-08-28 09:00:26]  INFO [uptime miner] Broadcasted vote: EENVote{Block: 0x6d0ae6, Height: 11759001, Round: 7, Address: 0x8d25fa2e7d}"
+	var fixed = "[2021-08-28 09:10:32.888] [info] [ThetaEdgeLauncher] [2021-08-28 09:10:32]  INFO [p2p] Already has sufficient number of peers, numPeers: 16, sufficientNumPeers: 16"
+
+	data.WriteUnparsed(fp, []data.UnparsedLine{
+		{Parser: "uptime", Line: broken, At: time.Now()},
+		{Parser: "p2p", Line: fixed, At: time.Now()},
+		{Parser: "p2p", Line: "no match log", At: time.Now()},
+	})
+
+	data.ResetNode()
+	defer data.ResetNode()
+	defer data.SetNodeAddress("")
+	data.SetNodeAddress("0x8d25fa2e7d")
+
+	// test dry run reports without rewriting file
+	var got []LineResult
+	rs, err := RetryUnparsed(fp, false, func(r LineResult) { got = append(got, r) })
+	if err != nil {
+		t.Fatalf("handlers.RetryUnparsed() returned error: %v", err)
+	}
+	if rs.Lines != 3 || rs.Fixed != 1 || rs.Unmatched != 1 || rs.Failed != 1 || len(got) != 2 {
+		t.Fatalf("handlers.RetryUnparsed() returned: %+v, %v", rs, got)
+	}
+	if ls, _ := data.ReadUnparsed(fp); len(ls) != 3 {
+		t.Fatalf("handlers.RetryUnparsed() left: %v lines, wanted: 3", len(ls))
+	}
+
+	// test line kept when record not delivered, ie server unreachable
+	rs, err = RetryUnparsed(fp, true, nil)
+	if err != nil || rs.Fixed != 0 || rs.Failed != 2 {
+		t.Fatalf("handlers.RetryUnparsed() returned: %+v, %v", rs, err)
+	}
+	if ls, _ := data.ReadUnparsed(fp); len(ls) != 2 || ls[1].Line != fixed {
+		t.Fatalf("handlers.RetryUnparsed() left: %+v, wanted undelivered line kept", ls)
+	}
+
+	// test none file path is nothing to retry
+	if rs, err = RetryUnparsed(fp+".none", true, nil); err != nil || rs.Lines != 0 {
+		t.Fatalf("handlers.RetryUnparsed() returned: %+v, %v", rs, err)
+	}
+}
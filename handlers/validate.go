@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"bufio"
+	"os"
+
+	"github.com/edgestats/edgestats-client/data"
+)
+
+type LineResult struct {
+	Line   int
+	Text   string
+	Filter int
+	Job    data.Job // zero unless parsed
+	Err    error
+}
+
+type ValidateStats struct {
+	Lines   int `json:"lines"`
+	Matched int `json:"matched"`
+	Valid   int `json:"valid"`
+	Sampled int `json:"sampled"`
+	Errors  int `json:"errors"`
+}
+
+func ValidateLog(fp string, fn func(LineResult)) (ValidateStats, error) {
+	var vs ValidateStats
+
+	f, err := os.Open(fp)
+	if err != nil {
+		return vs, err
+	}
+	defer f.Close()
+
+	// parse every line as run would, nothing is sent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		vs.Lines++
+		b := scanner.Bytes()
+
+		rs := checkLine(vs.Lines, b)
+		if len(rs) == 0 {
+			continue
+		}
+		vs.Matched++
+
+		for _, r := range rs {
+			switch true {
+			case r.Err == nil:
+				vs.Valid++
+			case data.IsThrottled(r.Err):
+				vs.Sampled++
+			default:
+				vs.Errors++
+			}
+
+			if fn != nil {
+				fn(r)
+			}
+		}
+	}
+
+	return vs, scanner.Err()
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/edgestats/edgestats-client/data"
+)
+
+func TestValidateLog(t *testing.T) {
+	// setup test variables
+	var got []LineResult
+	buf := append(logs, []byte("no match log\n")...)
+	fp := writeTempLog(t, buf)
+
+	// test lines parsed, p2p lines valid once address bootstrapped
+	vs, err := ValidateLog(fp, func(r LineResult) { got = append(got, r) })
+	if err != nil {
+		t.Fatalf("handlers.ValidateLog() returned error: %v", err)
+	}
+
+	if vs.Lines != 4 || vs.Matched != 3 || vs.Valid+vs.Errors != 3 || vs.Valid == 0 {
+		t.Fatalf("handlers.ValidateLog() returned: %+v, wanted lines: 4, matched: 3", vs)
+	}
+
+	if len(got) != 3 || got[2].Filter != data.UMFilter || got[2].Job.URL == "" || len(got[2].Job.Body) == 0 {
+		t.Fatalf("handlers.ValidateLog() returned: %+v", got)
+	}
+
+	// test none file path
+	if _, err = ValidateLog(fp+".none", nil); err == nil {
+		t.Fatalf("handlers.ValidateLog() returned: nil, wanted error")
+	}
+}
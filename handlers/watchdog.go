@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/edgestats/edgestats-client/data"
+)
+
+const (
+	NodeHealthy   = "healthy"
+	NodeStalled   = "stalled"    // log written but no uptime miner lines
+	NodeLogSilent = "log-silent" // log not written at all
+
+	defaultStallAfter  = 5 * time.Minute
+	defaultSilentAfter = 2 * time.Minute
+	nodeStateEvent     = "node_state"
+)
+
+var (
+	dog *Watchdog // nil disables liveness tracking
+)
+
+type NodeState struct {
+	State      string    `json:"state"`
+	PrevState  string    `json:"prev_state,omitempty"`
+	LastWrite  time.Time `json:"last_write"`
+	LastVote   time.Time `json:"last_vote"`
+	SinceWrite string    `json:"since_write"`
+	SinceVote  string    `json:"since_vote"`
+}
+
+type Watchdog struct {
+	mu          sync.Mutex
+	stallAfter  time.Duration
+	silentAfter time.Duration
+	now         func() time.Time
+	lastWrite   time.Time
+	lastVote    time.Time
+	state       string
+}
+
+func NewWatchdog(stallAfter, silentAfter time.Duration) *Watchdog {
+	// start counting from creation, ie client startup
+	now := time.Now()
+	return &Watchdog{
+		stallAfter:  stallAfter,
+		silentAfter: silentAfter,
+		now:         time.Now,
+		lastWrite:   now,
+		lastVote:    now,
+		state:       NodeHealthy,
+	}
+}
+
+func WatchdogFromEnv() (*Watchdog, error) {
+	stall, silent := defaultStallAfter, defaultSilentAfter
+
+	for _, d := range []struct {
+		name string
+		v    *time.Duration
+	}{
+		{"WATCHDOG_STALL_AFTER", &stall},
+		{"WATCHDOG_SILENT_AFTER", &silent},
+	} {
+		if v := os.Getenv(d.name); v != "" {
+			p, err := time.ParseDuration(v)
+			if err != nil || p <= 0 {
+				s := fmt.Sprintf("invalid %s: %s", d.name, v)
+				return nil, errors.New(s)
+			}
+			*d.v = p
+		}
+	}
+
+	return NewWatchdog(stall, silent), nil
+}
+
+func SetWatchdog(w *Watchdog) {
+	dog = w
+}
+
+func (w *Watchdog) MarkWrite() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lastWrite = w.now()
+}
+
+func (w *Watchdog) MarkVote(t time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// log time of the vote line, ie replayed old lines are not recent activity
+	now := w.now()
+	if t.IsZero() || t.After(now) {
+		t = now
+	}
+	if t.After(w.lastVote) {
+		w.lastVote = t
+	}
+}
+
+func (w *Watchdog) Check() (NodeState, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// record transition for next check
+	ns := w.classify()
+	if ns.State == w.state {
+		return ns, false
+	}
+	ns.PrevState = w.state
+	w.state = ns.State
+
+	return ns, true
+}
+
+func (w *Watchdog) Status() interface{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.classify()
+}
+
+func (w *Watchdog) classify() NodeState {
+	// caller holds lock
+	now := w.now()
+	sinceWrite := now.Sub(w.lastWrite)
+	sinceVote := now.Sub(w.lastVote)
+
+	// classify node by log activity
+	state := NodeHealthy
+	switch true {
+	case sinceWrite > w.silentAfter:
+		state = NodeLogSilent
+	case sinceVote > w.stallAfter:
+		state = NodeStalled
+	}
+
+	return NodeState{
+		State:      state,
+		LastWrite:  w.lastWrite.UTC(),
+		LastVote:   w.lastVote.UTC(),
+		SinceWrite: sinceWrite.Truncate(time.Second).String(),
+		SinceVote:  sinceVote.Truncate(time.Second).String(),
+	}
+}
+
+func (w *Watchdog) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// report transitions only
+			ns, changed := w.Check()
+			if !changed {
+				continue
+			}
+			if err := data.PublishEvent(data.NewEvent(nodeStateEvent, ns)); err != nil {
+				continue // perhaps log to log file
+			}
+		}
+	}
+}
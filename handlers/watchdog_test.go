@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestWatchdogCheck(t *testing.T) {
+	// setup test variables
+	var now = time.Date(2021, 8, 28, 9, 0, 0, 0, time.UTC)
+	var w = NewWatchdog(5*time.Minute, 2*time.Minute)
+
+	w.now = func() time.Time { return now }
+	w.lastWrite, w.lastVote = now, now
+
+	// test healthy without transition
+	if got, changed := w.Check(); got.State != NodeHealthy || changed {
+		t.Fatalf("handlers.Watchdog.Check() returned: %v, %v, wanted: %v, %v", got.State, changed, NodeHealthy, false)
+	}
+
+	// test log written but no votes
+	for i := 0; i < 6; i++ {
+		now = now.Add(time.Minute)
+		w.MarkWrite()
+	}
+
+	got, changed := w.Check()
+	if got.State != NodeStalled || got.PrevState != NodeHealthy || !changed {
+		t.Fatalf("handlers.Watchdog.Check() returned: %+v, %v, wanted: %v", got, changed, NodeStalled)
+	}
+
+	// test transition reported once
+	if _, changed := w.Check(); changed {
+		t.Fatalf("handlers.Watchdog.Check() returned changed: %v, wanted: %v", changed, false)
+	}
+
+	// test log not written
+	now = now.Add(3 * time.Minute)
+	if got, changed := w.Check(); got.State != NodeLogSilent || got.PrevState != NodeStalled || !changed {
+		t.Fatalf("handlers.Watchdog.Check() returned: %+v, %v, wanted: %v", got, changed, NodeLogSilent)
+	}
+
+	// test vote logged long ago is no recovery
+	w.MarkWrite()
+	w.MarkVote(now.Add(-time.Hour))
+	if got, _ := w.Check(); got.State != NodeStalled {
+		t.Fatalf("handlers.Watchdog.Check() returned: %+v, wanted: %v", got, NodeStalled)
+	}
+
+	// test recovery
+	w.MarkVote(now)
+	if got, changed := w.Check(); got.State != NodeHealthy || !changed {
+		t.Fatalf("handlers.Watchdog.Check() returned: %+v, %v, wanted: %v", got, changed, NodeHealthy)
+	}
+}
+
+func TestWatchdogStatus(t *testing.T) {
+	// setup test variables
+	var now = time.Now()
+	var w = NewWatchdog(time.Minute, time.Minute)
+
+	w.now = func() time.Time { return now.Add(time.Hour) }
+
+	// test status does not consume transition
+	if got := w.Status().(NodeState); got.State != NodeLogSilent {
+		t.Fatalf("handlers.Watchdog.Status() returned: %v, wanted: %v", got.State, NodeLogSilent)
+	}
+
+	if _, changed := w.Check(); !changed {
+		t.Fatalf("handlers.Watchdog.Check() returned changed: %v, wanted: %v", changed, true)
+	}
+}
+
+func TestProcessLogWatchdog(t *testing.T) {
+	// setup test variables
+	var start = time.Now().Add(-time.Hour)
+	var w = NewWatchdog(time.Minute, time.Minute)
+
+	w.lastVote = start
+	SetWatchdog(w)
+	defer SetWatchdog(nil)
+
+	// create tmp file with data
+	fp := writeTempLog(t, []byte("... [uptime miner] Start new round: 7\n"))
+
+	// test uptime miner line marks vote activity
+	if _, err := processLog(fp, 0); err != nil {
+		t.Fatalf("handlers.processLog() returned error: %v", err)
+	}
+
+	if !w.lastVote.After(start) {
+		t.Fatalf("handlers.processLog() did not mark vote: %v", w.lastVote)
+	}
+
+	// test replayed vote uses its log time
+	w.lastVote = start
+	fp = writeTempLog(t, []byte("[2021-08-28 09:00:26.951] [info] [ThetaEdgeLauncher] [2021-08-28 09:00:26]  INFO [uptime miner] Start new round: 7\n"))
+	if _, err := processLog(fp, 0); err != nil {
+		t.Fatalf("handlers.processLog() returned error: %v", err)
+	}
+
+	if !w.lastVote.Equal(start) {
+		t.Fatalf("handlers.processLog() marked vote: %v, wanted: %v", w.lastVote, start)
+	}
+}
+
+func TestProcessEventWatchdog(t *testing.T) {
+	// setup test variables
+	var start = time.Now().Add(-time.Hour)
+	var w = NewWatchdog(time.Minute, time.Minute)
+
+	SetWatchdog(w)
+	defer SetWatchdog(nil)
+
+	fp := writeTempLog(t, nil)
+	watcher, _ := fsnotify.NewWatcher()
+	defer watcher.Close()
+
+	// test created log file counts as write activity
+	w.lastWrite = start
+	if _, err := ProcessEvent(watcher, fsnotify.Event{Name: fp, Op: fsnotify.Create}, fp, 0); err != nil {
+		t.Fatalf("handlers.ProcessEvent() returned error: %v", err)
+	}
+
+	if !w.lastWrite.After(start) {
+		t.Fatalf("handlers.ProcessEvent() did not mark write: %v", w.lastWrite)
+	}
+}
+
+func TestWatchdogFromEnv(t *testing.T) {
+	defer os.Unsetenv("WATCHDOG_STALL_AFTER")
+
+	// test configured value
+	os.Setenv("WATCHDOG_STALL_AFTER", "10m")
+	w, err := WatchdogFromEnv()
+	if err != nil || w.stallAfter != 10*time.Minute || w.silentAfter != defaultSilentAfter {
+		t.Fatalf("handlers.WatchdogFromEnv() returned: %+v, %v", w, err)
+	}
+
+	// test invalid value
+	os.Setenv("WATCHDOG_STALL_AFTER", "-1m")
+	if _, err := WatchdogFromEnv(); err == nil {
+		t.Fatalf("handlers.WatchdogFromEnv() returned: %v, wanted error", err)
+	}
+}